@@ -10,6 +10,14 @@ func GetSample(cp []byte, size int, offset int) (int32, error) {
 	return getSample(cp, size, offset)
 }
 
+func PutSample(cp []byte, size int, offset int, value int32) error {
+	err := checkParameters(len(cp), size)
+	if err != nil {
+		return err
+	}
+	return putSample(cp, size, offset, value)
+}
+
 func Max(cp []byte, size int) (int32, error) {
 	err := checkParameters(len(cp), size)
 	if err != nil {
@@ -93,12 +101,12 @@ func RMS(cp []byte, size int) (int32, error) {
 		return 0, nil
 	}
 
-	var sumSquares int
+	var sumSquares float64
 	for _, sample := range samples {
-		sumSquares += int(sample * sample)
+		sumSquares += float64(sample) * float64(sample)
 	}
 
-	return int32(math.Sqrt(float64(sumSquares / sampleCount))), nil
+	return int32(math.Sqrt(sumSquares / float64(sampleCount))), nil
 }
 
 func FindFit(cp1 []byte, cp2 []byte) (int32, int32, error) {
@@ -372,7 +380,8 @@ func Mul(cp []byte, size int, factor float64) ([]byte, error) {
 		return nil, err
 	}
 
-	clip := getClipFunc(size)
+	// Clip before converting, out of range float to int32 conversions are undefined.
+	maxValue, minValue := float64(getMaxValue(size)), float64(getMinValue(size))
 	buf := make([]byte, len(cp))
 
 	samples, err := getSamples(cp, size)
@@ -381,7 +390,7 @@ func Mul(cp []byte, size int, factor float64) ([]byte, error) {
 	}
 
 	for i, sample := range samples {
-		clippedSample := clip(int32(float64(sample) * factor))
+		clippedSample := int32(math.Max(minValue, math.Min(maxValue, float64(sample)*factor)))
 		err := putSample(buf, size, i, clippedSample)
 		if err != nil {
 			return nil, err
@@ -401,7 +410,7 @@ func ToMono(cp []byte, size int, fac1, fac2 float64) ([]byte, error) {
 	buf := make([]byte, len(cp)/2)
 
 	for i := 0; i < sampleCount(cp, size); i += 2 {
-		lSample, err := getSample(cp, size, 1)
+		lSample, err := getSample(cp, size, i)
 		if err != nil {
 			return nil, err
 		}
@@ -455,6 +464,61 @@ func ToStereo(cp []byte, size int, fac1, fac2 float64) ([]byte, error) {
 	return buf, nil
 }
 
+// Remix mixes the channels of a fragment with a matrix, output channel i is
+// the sum of input channels j multiplied by matrix[i][j].
+func Remix(cp []byte, size int, matrix [][]float64) ([]byte, error) {
+	err := checkParameters(len(cp), size)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return nil, NewError("matrix should have at least one row and one column")
+	}
+
+	inChannels := len(matrix[0])
+	for _, row := range matrix {
+		if len(row) != inChannels {
+			return nil, NewError("matrix rows should have the same length")
+		}
+	}
+
+	if len(cp)%(size*inChannels) != 0 {
+		return nil, NewError("not a whole number of frames")
+	}
+
+	maxValue, minValue := float64(getMaxValue(size)), float64(getMinValue(size))
+	outChannels := len(matrix)
+	frameCount := len(cp) / (size * inChannels)
+	buf := make([]byte, frameCount*outChannels*size)
+	frame := make([]float64, inChannels)
+
+	for i := 0; i < frameCount; i++ {
+		for j := range frame {
+			sample, err := getSample(cp, size, i*inChannels+j)
+			if err != nil {
+				return nil, err
+			}
+			frame[j] = float64(sample)
+		}
+
+		for o, row := range matrix {
+			var sum float64
+			for j, fac := range row {
+				sum += frame[j] * fac
+			}
+
+			sample := int32(math.Max(minValue, math.Min(maxValue, sum)))
+			err := putSample(buf, size, i*outChannels+o, sample)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return buf, nil
+}
+
 func Add(cp1 []byte, cp2 []byte, size int) ([]byte, error) {
 	err := checkParameters(len(cp1), size)
 	if err != nil {
@@ -554,9 +618,9 @@ func Lin2Lin(cp []byte, size, size2 int) ([]byte, error) {
 		}
 
 		if size < size2 {
-			sample = sample << uint32(4*size2/size)
+			sample = sample << uint32(8*(size2-size))
 		} else if size > size2 {
-			sample = sample >> uint32(4*size/size2)
+			sample = sample >> uint32(8*(size-size2))
 		}
 
 		sample = overflow(sample, size2)
@@ -649,8 +713,7 @@ func Ratecv(cp []byte, size, nChannels, inRate, outRate, weightA, weightB int) (
 		for d < 0 {
 			if frameCount == 0 {
 				state := NewState(d, prevI, curI)
-				trimIndex := (outI * bytesPerFrame) - len(buf)
-				return buf[:trimIndex], state, nil
+				return buf[:outI*size], state, nil
 			}
 
 			for i := 0; i < nChannels; i++ {
@@ -659,7 +722,7 @@ func Ratecv(cp []byte, size, nChannels, inRate, outRate, weightA, weightB int) (
 				curI[i] = (weightA*curI[i] + weightB*prevI[i]) / (weightA + weightB)
 			}
 
-			frameCount += 1
+			frameCount -= 1
 			d += outRate
 		}
 
@@ -671,12 +734,10 @@ func Ratecv(cp []byte, size, nChannels, inRate, outRate, weightA, weightB int) (
 					return nil, nil, err
 				}
 				outI += 1
-				d -= inRate
 			}
+			d -= inRate
 		}
 	}
-
-	return nil, nil, nil
 }
 
 func sum2(cp1, cp2 []byte, length int) (int32, error) {
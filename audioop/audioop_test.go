@@ -0,0 +1,86 @@
+package audioop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMono(t *testing.T) {
+	cp := []byte{0x10, 0x00, 0x20, 0x00, 0x30, 0x00, 0x40, 0x00}
+	mono, err := ToMono(cp, 2, 0.5, 0.5)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x18, 0x00, 0x38, 0x00}, mono)
+}
+
+func TestMul(t *testing.T) {
+	cp := []byte{0x10, 0x00, 0xf0, 0xff}
+	doubled, err := Mul(cp, 2, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x20, 0x00, 0xe0, 0xff}, doubled)
+
+	// 32-bit samples are clipped before they overflow.
+	cp = []byte{0x00, 0x00, 0x00, 0x70, 0x00, 0x00, 0x00, 0x90, 0x00, 0x00, 0x00, 0x80}
+	boosted, err := Mul(cp, 4, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0x7f, 0x00, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x80}, boosted)
+
+	inverted, err := Mul(cp, 4, -1)
+	assert.Nil(t, err)
+	sample, _ := GetSample(inverted, 4, 2)
+	assert.Equal(t, int32(0x7fffffff), sample)
+}
+
+func TestRemix(t *testing.T) {
+	cp := []byte{0x10, 0x00, 0x20, 0x00, 0x30, 0x00, 0x40, 0x00}
+
+	swapped, err := Remix(cp, 2, [][]float64{{0, 1}, {1, 0}})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x20, 0x00, 0x10, 0x00, 0x40, 0x00, 0x30, 0x00}, swapped)
+
+	tripled, err := Remix(cp, 2, [][]float64{{1, 0}, {0, 1}, {0.5, 0.5}})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x10, 0x00, 0x20, 0x00, 0x18, 0x00, 0x30, 0x00, 0x40, 0x00, 0x38, 0x00}, tripled)
+
+	clipped, err := Remix([]byte{0x7f, 0x7f}, 1, [][]float64{{2, 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x7f}, clipped)
+
+	_, err = Remix(cp, 2, [][]float64{{1, 0}, {1}})
+	assert.Error(t, err)
+
+	_, err = Remix(cp, 2, [][]float64{{1, 0, 0}})
+	assert.Error(t, err)
+}
+
+func TestRatecv(t *testing.T) {
+	// Two stereo frames: (0, 100), (100, 200).
+	cp := []byte{0x00, 0x00, 0x64, 0x00, 0x64, 0x00, 0xc8, 0x00}
+
+	upsampled, _, err := Ratecv(cp, 2, 2, 1, 2, 1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3*4, len(upsampled))
+
+	downsampled, _, err := Ratecv(append(cp, cp...), 2, 2, 2, 1, 1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*4, len(downsampled))
+	left, _ := GetSample(downsampled, 2, 0)
+	right, _ := GetSample(downsampled, 2, 1)
+	assert.Equal(t, int32(0), left)
+	assert.Equal(t, int32(100), right)
+
+	_, _, err = Ratecv(cp[:6], 2, 2, 1, 2, 1, 0)
+	assert.Error(t, err)
+}
+
+func TestLin2Lin(t *testing.T) {
+	cp := []byte{0x34, 0x12, 0xcc, 0xed}
+
+	wide, err := Lin2Lin(cp, 2, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x34, 0x12, 0x00, 0x00, 0xcc, 0xed}, wide)
+
+	narrow, err := Lin2Lin(wide, 4, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x12, 0xed}, narrow)
+}
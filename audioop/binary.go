@@ -22,6 +22,17 @@ func Uint16LE(b []byte) uint16 {
 	return binary.LittleEndian.Uint16(b)
 }
 
+// Int24LE reads a signed 24-bit integer, sign-extended to 32 bits.
+func Int24LE(b []byte) int32 {
+	_ = b[2] // bounds check hint to compiler
+	return int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+}
+
+func Uint24LE(b []byte) uint32 {
+	_ = b[2] // bounds check hint to compiler
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
 func Int32LE(b []byte) int32 {
 	return int32(binary.LittleEndian.Uint32(b))
 }
@@ -30,6 +30,15 @@ func TestUint16(t *testing.T) {
 	assert.Equal(t, uint16(0xa212), Uint16LE(buf))
 }
 
+func TestInt24(t *testing.T) {
+	assert.Equal(t, int32(0x123456), Int24LE([]byte("\x56\x34\x12")))
+	assert.Equal(t, int32(-0x800000), Int24LE([]byte("\x00\x00\x80")))
+}
+
+func TestUint24(t *testing.T) {
+	assert.Equal(t, uint32(0x800000), Uint24LE([]byte("\x00\x00\x80")))
+}
+
 func TestInt32(t *testing.T) {
 	buf := make([]byte, 4)
 	binary.LittleEndian.PutUint32(buf, 10248)
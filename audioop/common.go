@@ -1,7 +1,6 @@
 package audioop
 
 import (
-	"bytes"
 	"encoding/binary"
 	"math"
 )
@@ -34,6 +33,10 @@ func getReadBinaryFunc(size int) (func(b []byte) int32, error) {
 		return func(b []byte) int32 {
 			return int32(Int16LE(b))
 		}, nil
+	case 3:
+		return func(b []byte) int32 {
+			return Int24LE(b)
+		}, nil
 	case 4:
 		return func(b []byte) int32 {
 			return Int32LE(b)
@@ -52,6 +55,8 @@ func getSample(cp []byte, size int, offset int) (int32, error) {
 		return int32(Int8LE(cp[start:end])), nil
 	case 2:
 		return int32(Int16LE(cp[start:end])), nil
+	case 3:
+		return Int24LE(cp[start:end]), nil
 	case 4:
 		return Int32LE(cp[start:end]), nil
 	default:
@@ -67,20 +72,21 @@ func putSample(cp []byte, size int, offset int, value int32) error {
 	start := offset * size
 	end := start + size
 
-	write := func(v interface{}) error {
-		return binary.Write(bytes.NewBuffer(cp[start:end]), binary.LittleEndian, v)
-	}
-
 	switch size {
 	case 1:
-		return write(int8(value))
+		cp[start] = byte(int8(value))
 	case 2:
-		return write(int16(value))
+		binary.LittleEndian.PutUint16(cp[start:end], uint16(int16(value)))
+	case 3:
+		cp[start] = byte(value)
+		cp[start+1] = byte(value >> 8)
+		cp[start+2] = byte(value >> 16)
 	case 4:
-		return write(int32(value))
+		binary.LittleEndian.PutUint32(cp[start:end], uint32(value))
 	default:
-		return NewError("size should be 1, 2, or 4")
+		return NewError("size should be 1, 2, 3 or 4")
 	}
+	return nil
 }
 
 func overflow(value int32, size int) int32 {
@@ -110,6 +116,8 @@ func getMaxValue(size int) int32 {
 		return 0x7f
 	case 2:
 		return 0x7fff
+	case 3:
+		return 0x7fffff
 	case 4:
 		return 0x7fffffff
 	default:
@@ -123,6 +131,8 @@ func getMinValue(size int) int32 {
 		return -0x80
 	case 2:
 		return -0x8000
+	case 3:
+		return -0x800000
 	case 4:
 		return -0x80000000
 	default:
@@ -144,8 +154,8 @@ func checkParameters(length int, size int) error {
 }
 
 func checkSize(size int) error {
-	if size != 1 && size != 2 && size != 4 {
-		return NewError("size should be 1, 2, or 4")
+	if size < 1 || size > 4 {
+		return NewError("size should be 1, 2, 3 or 4")
 	}
 	return nil
 }
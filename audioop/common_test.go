@@ -9,6 +9,7 @@ import (
 func Test_checkParameters(t *testing.T) {
 	assert.Nil(t, checkParameters(12, 1))
 	assert.Nil(t, checkParameters(12, 2))
+	assert.Nil(t, checkParameters(12, 3))
 	assert.Nil(t, checkParameters(12, 4))
 
 	assert.Error(t, checkParameters(0, 0))
@@ -18,11 +19,42 @@ func Test_checkParameters(t *testing.T) {
 func Test_getMaxValue(t *testing.T) {
 	assert.Equal(t, int32(0x7f), getMaxValue(1))
 	assert.Equal(t, int32(0x7fff), getMaxValue(2))
+	assert.Equal(t, int32(0x7fffff), getMaxValue(3))
 	assert.Equal(t, int32(0x7fffffff), getMaxValue(4))
 }
 
 func Test_getMinValue(t *testing.T) {
 	assert.Equal(t, int32(-0x80), getMinValue(1))
 	assert.Equal(t, int32(-0x8000), getMinValue(2))
+	assert.Equal(t, int32(-0x800000), getMinValue(3))
 	assert.Equal(t, int32(-0x80000000), getMinValue(4))
 }
+
+func Test_putSample(t *testing.T) {
+	buf := make([]byte, 6)
+	assert.Nil(t, putSample(buf, 2, 0, 1))
+	assert.Nil(t, putSample(buf, 2, 2, -2))
+	assert.Equal(t, []byte{0x01, 0x00, 0x00, 0x00, 0xfe, 0xff}, buf)
+
+	buf = make([]byte, 4)
+	assert.Nil(t, putSample(buf, 4, 0, -0x80000000))
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x80}, buf)
+
+	buf = make([]byte, 6)
+	assert.Nil(t, putSample(buf, 3, 0, 0x123456))
+	assert.Nil(t, putSample(buf, 3, 1, -2))
+	assert.Equal(t, []byte{0x56, 0x34, 0x12, 0xfe, 0xff, 0xff}, buf)
+
+	assert.Error(t, putSample(buf, 5, 0, 0))
+}
+
+func Test_getSample24(t *testing.T) {
+	buf := []byte{0x56, 0x34, 0x12, 0xfe, 0xff, 0xff}
+	sample, err := getSample(buf, 3, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0x123456), sample)
+
+	samples, err := getSamples(buf, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{0x123456, -2}, samples)
+}
@@ -0,0 +1,318 @@
+package godub
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/iFaceless/godub/audioop"
+)
+
+// Standard channel mixing matrices, output channel i is the sum of input channels j
+// multiplied by matrix[i][j]. Multichannel layouts follow the WAVE channel order:
+// 5.1 is FL, FR, FC, LFE, BL, BR and 7.1 is FL, FR, FC, LFE, BL, BR, SL, SR.
+var (
+	MonoToStereo = [][]float64{
+		{1},
+		{1},
+	}
+	StereoToMono = [][]float64{
+		{0.5, 0.5},
+	}
+	// MonoToSurround51 places the mono channel in the front center.
+	MonoToSurround51 = [][]float64{
+		{0}, {0}, {1}, {0}, {0}, {0},
+	}
+	// MonoToSurround71 places the mono channel in the front center.
+	MonoToSurround71 = [][]float64{
+		{0}, {0}, {1}, {0}, {0}, {0}, {0}, {0},
+	}
+	// StereoToSurround51 keeps left and right in the front, other channels are silent.
+	StereoToSurround51 = [][]float64{
+		{1, 0},
+		{0, 1},
+		{0, 0},
+		{0, 0},
+		{0, 0},
+		{0, 0},
+	}
+	// StereoToSurround71 keeps left and right in the front, other channels are silent.
+	StereoToSurround71 = [][]float64{
+		{1, 0},
+		{0, 1},
+		{0, 0},
+		{0, 0},
+		{0, 0},
+		{0, 0},
+		{0, 0},
+		{0, 0},
+	}
+	// Surround51ToStereo is the ITU-R BS.775 downmix, LFE is discarded. It has no headroom,
+	// loud PCM material clips unless it's converted to float first.
+	Surround51ToStereo = [][]float64{
+		{1, 0, minus3dB, 0, minus3dB, 0},
+		{0, 1, minus3dB, 0, 0, minus3dB},
+	}
+	// Surround71ToStereo is the ITU-R BS.775 downmix extended to side channels, LFE is discarded.
+	// It has no headroom, loud PCM material clips unless it's converted to float first.
+	Surround71ToStereo = [][]float64{
+		{1, 0, minus3dB, 0, minus3dB, 0, minus3dB, 0},
+		{0, 1, minus3dB, 0, 0, minus3dB, 0, minus3dB},
+	}
+	// Surround51ToSurround71 keeps the 5.1 channels, side channels are silent.
+	Surround51ToSurround71 = [][]float64{
+		{1, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0, 0},
+		{0, 0, 1, 0, 0, 0},
+		{0, 0, 0, 1, 0, 0},
+		{0, 0, 0, 0, 1, 0},
+		{0, 0, 0, 0, 0, 1},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+	}
+	// Surround71ToSurround51 folds the side channels into the back channels at -3dB.
+	Surround71ToSurround51 = [][]float64{
+		{1, 0, 0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0, 0, 0, 0},
+		{0, 0, 1, 0, 0, 0, 0, 0},
+		{0, 0, 0, 1, 0, 0, 0, 0},
+		{0, 0, 0, 0, 1, 0, minus3dB, 0},
+		{0, 0, 0, 0, 0, 1, 0, minus3dB},
+	}
+	// SwapStereo swaps the left and right channels.
+	SwapStereo = [][]float64{
+		{0, 1},
+		{1, 0},
+	}
+)
+
+// PickChannel returns the matrix to extract channel `index` of `channels` channels as mono.
+func PickChannel(channels, index int) [][]float64 {
+	row := make([]float64, channels)
+	if index >= 0 && index < channels {
+		row[index] = 1
+	}
+	return [][]float64{row}
+}
+
+// minus3dB is the ratio of -3dB, used by the ITU downmix coefficients.
+const minus3dB = 0.7071067811865476
+
+// standardChannelMatrices are indexed by the number of input and output channels.
+var standardChannelMatrices = map[[2]int][][]float64{
+	{1, 2}: MonoToStereo,
+	{2, 1}: StereoToMono,
+	{1, 6}: MonoToSurround51,
+	{1, 8}: MonoToSurround71,
+	{2, 6}: StereoToSurround51,
+	{2, 8}: StereoToSurround71,
+	{6, 2}: Surround51ToStereo,
+	{8, 2}: Surround71ToStereo,
+	{6, 8}: Surround51ToSurround71,
+	{8, 6}: Surround71ToSurround51,
+}
+
+// standardChannelMatrix returns the matrix to mix `from` channels into `to` channels.
+func standardChannelMatrix(from, to int) ([][]float64, error) {
+	known := standardChannelMatrices
+	if matrix, ok := known[[2]int{from, to}]; ok {
+		return matrix, nil
+	}
+
+	// Go through stereo when mixing down, e.g. 5.1 to mono.
+	toStereo, ok1 := known[[2]int{from, 2}]
+	fromStereo, ok2 := known[[2]int{2, to}]
+	if to <= 2 && ok1 && ok2 {
+		return multiplyMatrices(fromStereo, toStereo), nil
+	}
+
+	switch {
+	case to == 1:
+		// Average all channels.
+		row := make([]float64, from)
+		for i := range row {
+			row[i] = 1 / float64(from)
+		}
+		return [][]float64{row}, nil
+	case from == 1:
+		// Copy to all channels.
+		matrix := make([][]float64, to)
+		for i := range matrix {
+			matrix[i] = []float64{1}
+		}
+		return matrix, nil
+	default:
+		return foldChannelMatrix(from, to), nil
+	}
+}
+
+// foldChannelMatrix copies the channels both layouts have, extra output channels are
+// silent and extra input channels are folded at -3dB into the output channels in turn.
+func foldChannelMatrix(from, to int) [][]float64 {
+	matrix := make([][]float64, to)
+	for i := range matrix {
+		matrix[i] = make([]float64, from)
+		if i < from {
+			matrix[i][i] = 1
+		}
+	}
+
+	for j := to; j < from; j++ {
+		matrix[j%to][j] = minus3dB
+	}
+	return matrix
+}
+
+// multiplyMatrices returns a x b, which applies b and then a.
+func multiplyMatrices(a, b [][]float64) [][]float64 {
+	result := make([][]float64, len(a))
+	for i := range a {
+		result[i] = make([]float64, len(b[0]))
+		for j := range result[i] {
+			for k := range b {
+				result[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return result
+}
+
+// ForkWithChannelMatrix builds each output channel as a weighted sum of the input channels:
+// output channel i is the sum of input channels j multiplied by matrix[i][j]. The matrix
+// should have one column per input channel, e.g. SwapStereo swaps left and right and
+// PickChannel extracts a single channel. Mixed samples are clipped, except for float samples.
+func (seg *AudioSegment) ForkWithChannelMatrix(matrix [][]float64) (*AudioSegment, error) {
+	if len(matrix) == 0 {
+		return nil, NewAudioSegmentError("matrix should have at least one row")
+	}
+
+	for _, row := range matrix {
+		if len(row) != int(seg.channels) {
+			return nil, NewAudioSegmentError("matrix should have one column for each of the %d channels", seg.channels)
+		}
+	}
+
+	if seg.sampleFormat == SampleFormatFloat {
+		samples, err := seg.channelSamples()
+		if err != nil {
+			return nil, err
+		}
+
+		mixed := make([][]float64, len(matrix))
+		for i, row := range matrix {
+			mixed[i] = make([]float64, len(samples[0]))
+			for j, weight := range row {
+				for k, v := range samples[j] {
+					mixed[i][k] += weight * v
+				}
+			}
+		}
+		return seg.deriveFromChannelSamples(mixed)
+	}
+
+	data, err := seg.signedData()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = audioop.Remix(data, int(seg.sampleWidth), matrix)
+	if err != nil {
+		return nil, err
+	}
+
+	if seg.sampleWidth == 1 {
+		data, err = audioop.Bias(data, 1, 128)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	channels := len(matrix)
+	frameWidth := channels * int(seg.sampleWidth)
+	return seg.derive(data, Channels(uint16(channels)), FrameWidth(uint32(frameWidth)))
+}
+
+// SplitToMono splits the segment into one mono segment per channel.
+func (seg *AudioSegment) SplitToMono() []*AudioSegment {
+	if seg.channels == 1 {
+		return []*AudioSegment{seg}
+	}
+
+	size := int(seg.sampleWidth)
+	frameWidth := int(seg.frameWidth)
+	frameCount := int(seg.FrameCount())
+
+	segments := make([]*AudioSegment, 0, seg.channels)
+	for c := 0; c < int(seg.channels); c++ {
+		data := make([]byte, frameCount*size)
+		for i := 0; i < frameCount; i++ {
+			offset := i*frameWidth + c*size
+			copy(data[i*size:(i+1)*size], seg.data[offset:offset+size])
+		}
+
+		mono := *seg
+		mono.data = data
+		mono.channels = 1
+		mono.frameWidth = uint32(size)
+		mono.rms = nil
+		segments = append(segments, &mono)
+	}
+
+	return segments
+}
+
+// NewAudioSegmentFromMonoSegments interleaves mono segments into a multi-channel segment,
+// the first segment becomes the first channel, and so on. Segments are synced to the
+// highest frame rate and sample width, a length difference up to 2ms is filled with silence.
+func NewAudioSegmentFromMonoSegments(segments ...*AudioSegment) (*AudioSegment, error) {
+	if len(segments) == 0 {
+		return nil, NewAudioSegmentError("at least one mono segment is required")
+	}
+
+	for _, seg := range segments {
+		if seg.channels != 1 {
+			return nil, NewAudioSegmentError("all segments should be mono, got %d channels", seg.channels)
+		}
+	}
+
+	synced, err := sync(ResampleDefault, segments...)
+	if err != nil {
+		return nil, err
+	}
+
+	first := synced[0]
+	size := int(first.sampleWidth)
+
+	frameCount := 0
+	for _, seg := range synced {
+		if n := int(seg.FrameCount()); n > frameCount {
+			frameCount = n
+		}
+	}
+
+	silence := []byte{0}
+	if size == 1 {
+		// 8-bit audio is stored as unsigned data.
+		silence = []byte{0x80}
+	}
+
+	channels := make([][]byte, len(synced))
+	for c, seg := range synced {
+		missingFrames := frameCount - int(seg.FrameCount())
+		if float64(missingFrames) > first.FrameCountIn(2*time.Millisecond) {
+			return nil, NewAudioSegmentError("mono segments should have the same length")
+		}
+		channel := make([]byte, 0, frameCount*size)
+		channel = append(channel, seg.data[:int(seg.FrameCount())*size]...)
+		channels[c] = append(channel, bytes.Repeat(silence, missingFrames*size)...)
+	}
+
+	frameWidth := size * len(channels)
+	data := make([]byte, frameCount*frameWidth)
+	for i := 0; i < frameCount; i++ {
+		for c, channel := range channels {
+			copy(data[i*frameWidth+c*size:], channel[i*size:(i+1)*size])
+		}
+	}
+
+	return first.derive(data, Channels(uint16(len(channels))), FrameWidth(uint32(frameWidth)))
+}
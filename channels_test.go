@@ -0,0 +1,137 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitToMono(t *testing.T) {
+	left := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+	right := newSineSegment(t, 220, 0.3, 100*time.Millisecond, 1, 2)
+
+	stereo, err := NewAudioSegmentFromMonoSegments(left, right)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(2), stereo.Channels())
+	assert.Equal(t, uint32(4), stereo.FrameWidth())
+	assert.Equal(t, left.Duration(), stereo.Duration())
+
+	monos := stereo.SplitToMono()
+	assert.Len(t, monos, 2)
+	assert.True(t, monos[0].Equal(left))
+	assert.True(t, monos[1].Equal(right))
+	assert.Equal(t, uint16(1), monos[1].Channels())
+
+	_, err = NewAudioSegmentFromMonoSegments(left, stereo)
+	assert.Error(t, err)
+
+	short := newSineSegment(t, 440, 0.5, 50*time.Millisecond, 1, 2)
+	_, err = NewAudioSegmentFromMonoSegments(left, short)
+	assert.Error(t, err)
+}
+
+func TestForkWithChannels(t *testing.T) {
+	mono := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+
+	surround, err := mono.ForkWithChannels(6)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(6), surround.Channels())
+	assert.Equal(t, uint32(12), surround.FrameWidth())
+	channels := surround.SplitToMono()
+	assert.True(t, channels[2].Equal(mono))
+	assert.Equal(t, 0.0, channels[0].Max())
+
+	stereo, err := surround.ForkWithChannels(2)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(mono.MaxDBFS())-3, float64(stereo.MaxDBFS()), 0.05)
+
+	back, err := surround.ForkWithChannels(1)
+	assert.Nil(t, err)
+	assert.Equal(t, mono.Duration(), back.Duration())
+
+	quad, err := mono.ForkWithChannels(4)
+	assert.Nil(t, err)
+	assert.True(t, quad.SplitToMono()[3].Equal(mono))
+
+	// Other layouts keep the shared channels and fold the others in.
+	folded, err := surround.ForkWithChannels(4)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(4), folded.Channels())
+	assert.True(t, folded.SplitToMono()[2].Equal(mono))
+
+	stereo, err = newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 2).Append(quad)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(4), stereo.Channels())
+
+	// Syncing mixes every segment up to the most channels.
+	combined, err := mono.Append(surround)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(6), combined.Channels())
+
+	unsigned := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 1)
+	downmixed, err := unsigned.ForkWithChannels(1)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(unsigned.DBFS()), float64(downmixed.DBFS()), 0.1)
+}
+
+func TestForkWithChannelsSurround(t *testing.T) {
+	surround51 := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 6, 2)
+
+	surround71, err := surround51.ForkWithChannels(8)
+	assert.Nil(t, err)
+	channels := surround71.SplitToMono()
+	for c := 0; c < 6; c++ {
+		assert.InDelta(t, float64(surround51.MaxDBFS()), float64(channels[c].MaxDBFS()), 0.01)
+	}
+	assert.Equal(t, 0.0, channels[6].Max())
+	assert.Equal(t, 0.0, channels[7].Max())
+
+	back, err := surround71.ForkWithChannels(6)
+	assert.Nil(t, err)
+	assert.True(t, surround51.Equal(back))
+
+	// Side channels are folded into the back channels.
+	full := newSineSegment(t, 440, 0.25, 100*time.Millisecond, 8, 2)
+	folded, err := full.ForkWithChannels(6)
+	assert.Nil(t, err)
+	channels = folded.SplitToMono()
+	assert.InDelta(t, float64(full.MaxDBFS()), float64(channels[0].MaxDBFS()), 0.01)
+	assert.InDelta(t, float64(full.MaxDBFS())+4.65, float64(channels[4].MaxDBFS()), 0.05)
+
+	// Syncing 5.1 with 7.1 keeps all channels.
+	combined, err := surround51.Append(surround71)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(8), combined.Channels())
+	assert.InDelta(t, float64(surround51.MaxDBFS()), float64(combined.SplitToMono()[5].MaxDBFS()), 0.01)
+}
+
+func TestForkWithChannelMatrix(t *testing.T) {
+	left := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+	right := newSineSegment(t, 220, 0.3, 100*time.Millisecond, 1, 2)
+	stereo, _ := NewAudioSegmentFromMonoSegments(left, right)
+
+	swapped, err := stereo.ForkWithChannelMatrix(SwapStereo)
+	assert.Nil(t, err)
+	channels := swapped.SplitToMono()
+	assert.True(t, channels[0].Equal(right))
+	assert.True(t, channels[1].Equal(left))
+
+	picked, err := stereo.ForkWithChannelMatrix(PickChannel(2, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(1), picked.Channels())
+	assert.True(t, picked.Equal(right))
+
+	_, err = stereo.ForkWithChannelMatrix(PickChannel(3, 0))
+	assert.Error(t, err)
+
+	_, err = stereo.ForkWithChannelMatrix([][]float64{{1, 0}, {1}})
+	assert.Error(t, err)
+
+	float, err := stereo.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+	for _, matrix := range [][][]float64{{{1, 0}, {1}}, {{1, 0}, {1, 0, 1}}, {}} {
+		_, err = float.ForkWithChannelMatrix(matrix)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,101 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+type CompressorConfig struct {
+	// Threshold is the level above which the gain is reduced, default to -20dBFS when nil.
+	Threshold *Volume
+	// Ratio is the amount of compression, 4 means 4dB over the threshold
+	// comes out as 1dB over it. Default to 4.
+	Ratio float64
+	// Attack is how fast the compressor reacts to rising levels, default to 5ms.
+	Attack time.Duration
+	// Release is how fast the compressor recovers from falling levels, default to 50ms.
+	Release time.Duration
+	// Knee is the width of the soft knee around the threshold, 0 means a hard knee.
+	Knee Volume
+	// MakeupGain is applied to the whole segment after compression.
+	MakeupGain Volume
+	// Window is the length of the RMS window used to detect levels, default to 10ms.
+	Window time.Duration
+}
+
+func (config CompressorConfig) withDefaults() CompressorConfig {
+	if config.Threshold == nil {
+		config.Threshold = VolumePtr(-20)
+	}
+
+	if config.Ratio == 0 {
+		config.Ratio = 4
+	}
+
+	if config.Attack == 0 {
+		config.Attack = 5 * time.Millisecond
+	}
+
+	if config.Release == 0 {
+		config.Release = 50 * time.Millisecond
+	}
+
+	if config.Window == 0 {
+		config.Window = 10 * time.Millisecond
+	}
+	return config
+}
+
+// gainReduction returns the gain (in dB, never positive) applied to a signal at `level` dB.
+func (config CompressorConfig) gainReduction(level float64) float64 {
+	threshold, knee := float64(*config.Threshold), float64(config.Knee)
+	overshoot := level - threshold
+
+	var output float64
+	switch {
+	case 2*overshoot < -knee:
+		output = level
+	case knee > 0 && 2*math.Abs(overshoot) <= knee:
+		// Inside the soft knee, the ratio changes smoothly from 1 to `Ratio`.
+		output = level + (1/config.Ratio-1)*math.Pow(overshoot+knee/2, 2)/(2*knee)
+	default:
+		output = threshold + overshoot/config.Ratio
+	}
+	return output - level
+}
+
+// CompressDynamicRange reduces the level of loud parts of the segment. Levels are
+// detected with a windowed RMS over all channels, so that every channel gets the
+// same gain and the stereo image is kept.
+func (seg *AudioSegment) CompressDynamicRange(config *CompressorConfig) (*AudioSegment, error) {
+	c := config.withDefaults()
+	if c.Ratio < 1 {
+		return nil, NewAudioSegmentError("ratio should be >= 1")
+	}
+
+	if c.Knee < 0 || c.Attack < 0 || c.Release < 0 || c.Window < 0 {
+		return nil, NewAudioSegmentError("knee, attack, release and window should be positive")
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	levels := windowedLevels(samples, int(seg.FrameCountIn(c.Window)))
+	if len(levels) == 0 {
+		return seg, nil
+	}
+
+	makeup := float64(c.MakeupGain)
+	envelope := newEnvelopeFollower(c.Attack, c.Release, seg.frameRate, ratioToDB(levels[0]))
+	for i, level := range levels {
+		smoothed := envelope.next(ratioToDB(level))
+		ratio := Volume(c.gainReduction(smoothed) + makeup).ToRatio(true)
+		for _, channel := range samples {
+			channel[i] *= ratio
+		}
+	}
+
+	return seg.deriveFromChannelSamples(samples)
+}
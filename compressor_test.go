@@ -0,0 +1,42 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressDynamicRange(t *testing.T) {
+	loud := newSineSegment(t, 440, 0.9, time.Second, 2, 2)
+	compressed, err := loud.CompressDynamicRange(&CompressorConfig{Threshold: VolumePtr(-20), Ratio: 4})
+	assert.Nil(t, err)
+
+	tail, _ := compressed.Slice(500*time.Millisecond, time.Second)
+	assert.InDelta(t, -16, float64(tail.DBFS()), 0.5)
+
+	quiet := newSineSegment(t, 440, 0.01, time.Second, 2, 2)
+	compressed, err = quiet.CompressDynamicRange(&CompressorConfig{Threshold: VolumePtr(-20), Ratio: 4, Knee: 6})
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(quiet.DBFS()), float64(compressed.DBFS()), 0.1)
+
+	_, err = quiet.CompressDynamicRange(&CompressorConfig{Ratio: 0.5})
+	assert.Error(t, err)
+}
+
+func TestCompressorGainReduction(t *testing.T) {
+	config := CompressorConfig{Threshold: VolumePtr(-20), Ratio: 4, Knee: 10}
+	assert.Equal(t, 0.0, config.gainReduction(-40))
+	assert.InDelta(t, -15, config.gainReduction(0), 1e-9)
+	assert.True(t, config.gainReduction(-20) < 0)
+
+	// A hard knee doesn't reduce the gain at the threshold.
+	config = CompressorConfig{Threshold: VolumePtr(-20), Ratio: 4}
+	assert.Equal(t, 0.0, config.gainReduction(-20))
+	assert.InDelta(t, -15, config.gainReduction(0), 1e-9)
+
+	config = CompressorConfig{Threshold: VolumePtr(0), Ratio: 4}.withDefaults()
+	assert.Equal(t, Volume(0), *config.Threshold)
+	assert.Equal(t, 0.0, config.gainReduction(-3))
+	assert.InDelta(t, -4.5, config.gainReduction(6), 1e-9)
+}
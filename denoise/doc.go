@@ -0,0 +1,7 @@
+// Package denoise removes stationary background noise, such as hiss or hum, from audio
+// segments. A noise profile is learned from a part of the recording that only contains
+// noise, then every frequency band of the audio is attenuated according to how much of
+// it is noise, with spectral subtraction or a Wiener filter:
+//  1. https://en.wikipedia.org/wiki/Spectral_subtraction
+//  2. https://en.wikipedia.org/wiki/Wiener_filter
+package denoise
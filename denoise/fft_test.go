@@ -0,0 +1,56 @@
+package denoise
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFFT(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	x := make([]complex128, 64)
+	for i := range x {
+		x[i] = complex(r.Float64()-0.5, r.Float64()-0.5)
+	}
+
+	// Compare with the definition of the discrete Fourier transform.
+	expected := make([]complex128, len(x))
+	for k := range expected {
+		for n, v := range x {
+			expected[k] += v * cmplx.Rect(1, -2*math.Pi*float64(k*n)/float64(len(x)))
+		}
+	}
+
+	actual := make([]complex128, len(x))
+	copy(actual, x)
+	fft(actual, false)
+	for k := range expected {
+		assert.InDelta(t, 0, cmplx.Abs(expected[k]-actual[k]), 1e-9)
+	}
+
+	fft(actual, true)
+	for i := range x {
+		assert.InDelta(t, 0, cmplx.Abs(x[i]-actual[i]), 1e-12)
+	}
+}
+
+func TestSTFTRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	samples := make([]float64, 5000)
+	for i := range samples {
+		samples[i] = r.Float64() - 0.5
+	}
+
+	s := newSTFT(8000)
+	assert.Equal(t, 256, s.size)
+	assert.Equal(t, 2048, newSTFT(48000).size)
+
+	rebuilt := s.inverse(s.forward(samples), len(samples))
+	assert.Len(t, rebuilt, len(samples))
+	for i := range samples {
+		assert.InDelta(t, samples[i], rebuilt[i], 1e-9)
+	}
+}
@@ -0,0 +1,47 @@
+package denoise
+
+import (
+	"math/cmplx"
+
+	"github.com/iFaceless/godub"
+)
+
+// Profile is the average power spectrum of the background noise of a recording.
+type Profile struct {
+	frameRate uint32
+	// power is the mean power of each frequency bin, averaged over all channels.
+	power []float64
+}
+
+// NewProfile learns the noise profile from a segment that only contains noise, e.g. a
+// second of room tone cut out with Slice. It should be at least as long as a frame (32ms),
+// a longer segment gives a better estimate.
+func NewProfile(noise *godub.AudioSegment) (*Profile, error) {
+	samples, err := noise.GetChannelFloatSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	t := newSTFT(noise.FrameRate())
+	if len(samples) == 0 || len(samples[0]) < t.size {
+		return nil, NewError("noise should be at least %d frames long", t.size)
+	}
+
+	power := make([]float64, t.bins())
+	count := 0
+	for _, channel := range samples {
+		// Only keep frames that are fully inside the noise, the padding would lower the estimate.
+		frames := t.forward(channel)
+		for f := t.size / t.hop; f*t.hop <= len(channel); f++ {
+			for k, v := range frames[f] {
+				power[k] += real(v * cmplx.Conj(v))
+			}
+			count++
+		}
+	}
+
+	for k := range power {
+		power[k] /= float64(count)
+	}
+	return &Profile{frameRate: noise.FrameRate(), power: power}, nil
+}
@@ -0,0 +1,154 @@
+package denoise
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/iFaceless/godub"
+)
+
+// Method is the rule used to attenuate each frequency bin.
+type Method int
+
+const (
+	// SpectralSubtraction removes the noise power from the power of each bin.
+	// It's aggressive, and may leave some "musical noise" at high reductions.
+	SpectralSubtraction Method = iota
+	// Wiener estimates the signal to noise ratio of each bin over time with the
+	// decision-directed approach, which sounds more natural on speech.
+	Wiener
+)
+
+type Config struct {
+	// Method is the reduction rule, default to SpectralSubtraction.
+	Method Method
+	// Reduction is the highest attenuation of noisy bins in dB, default to 12dB. A lower
+	// reduction keeps some of the noise, which sounds more natural.
+	Reduction godub.Volume
+	// Oversubtraction multiplies the noise profile, higher values remove more noise at
+	// the cost of the signal. Default to 3.
+	Oversubtraction float64
+}
+
+func (config Config) withDefaults() Config {
+	if config.Reduction == 0 {
+		config.Reduction = 12
+	}
+
+	if config.Oversubtraction == 0 {
+		config.Oversubtraction = 3
+	}
+	return config
+}
+
+// wienerSmoothing is the weight of the previous frame in the decision-directed estimate.
+const wienerSmoothing = 0.98
+
+// Reduce removes the noise of the profile from the segment, every channel is processed
+// separately. The segment should have the frame rate of the noise the profile was learned
+// from, the result keeps the sample format of the segment.
+func (p *Profile) Reduce(seg *godub.AudioSegment, config *Config) (*godub.AudioSegment, error) {
+	c := config.withDefaults()
+	if c.Reduction < 0 {
+		return nil, NewError("reduction should be positive")
+	}
+
+	if c.Oversubtraction < 0 {
+		return nil, NewError("oversubtraction should be positive")
+	}
+
+	if c.Method != SpectralSubtraction && c.Method != Wiener {
+		return nil, NewError("unknown method: %d", c.Method)
+	}
+
+	if seg.FrameRate() != p.frameRate {
+		return nil, NewError("the noise profile was learned at %dHz, got %dHz", p.frameRate, seg.FrameRate())
+	}
+
+	samples, err := seg.GetChannelFloatSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	t := newSTFT(p.frameRate)
+	floor := (-c.Reduction).ToRatio(true)
+	noise := make([]float64, len(p.power))
+	for k, v := range p.power {
+		noise[k] = math.Max(v*c.Oversubtraction, 1e-20)
+	}
+
+	for ch, channel := range samples {
+		frames := t.forward(channel)
+
+		// The previous clean power of each bin, for the decision-directed estimate.
+		previous := make([]float64, t.bins())
+		smoothed := make([]float64, t.bins())
+		for _, frame := range frames {
+			smoothPower(frame, smoothed)
+			for k, v := range frame {
+				power := real(v * cmplx.Conj(v))
+				posterior := smoothed[k] / noise[k]
+
+				var gain float64
+				switch c.Method {
+				case SpectralSubtraction:
+					gain = math.Sqrt(math.Max(0, 1-1/posterior))
+				case Wiener:
+					prior := wienerSmoothing*previous[k]/noise[k] + (1-wienerSmoothing)*math.Max(posterior-1, 0)
+					gain = prior / (1 + prior)
+				}
+
+				gain = math.Max(gain, floor)
+				previous[k] = gain * gain * power
+				frame[k] = v * complex(gain, 0)
+			}
+		}
+
+		samples[ch] = t.inverse(frames, len(channel))
+	}
+
+	reduced, err := godub.NewAudioSegmentFromChannelFloatSamples(samples, seg.FrameRate())
+	if err != nil {
+		return nil, err
+	}
+	return reduced.ForkWithSampleFormat(seg.SampleFormat(), int(seg.SampleWidth()))
+}
+
+// powerSmoothing is the weight of the previous frame in the smoothed power spectrum.
+const powerSmoothing = 0.5
+
+// smoothPower updates the power spectrum `smoothed` with the next frame. The power is
+// averaged over neighbouring bins and over time, which reduces the random variations of
+// noise that would otherwise get through as "musical noise".
+func smoothPower(frame []complex128, smoothed []float64) {
+	power := make([]float64, len(frame))
+	for k, v := range frame {
+		power[k] = real(v * cmplx.Conj(v))
+	}
+
+	for k := range smoothed {
+		from, to := k-1, k+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(power)-1 {
+			to = len(power) - 1
+		}
+
+		var sum float64
+		for i := from; i <= to; i++ {
+			sum += power[i]
+		}
+		average := sum / float64(to-from+1)
+		smoothed[k] = powerSmoothing*smoothed[k] + (1-powerSmoothing)*average
+	}
+}
+
+// Reduce learns the noise profile from `noise` and removes it from the segment.
+func Reduce(seg, noise *godub.AudioSegment, config *Config) (*godub.AudioSegment, error) {
+	profile, err := NewProfile(noise)
+	if err != nil {
+		return nil, err
+	}
+	return profile.Reduce(seg, config)
+}
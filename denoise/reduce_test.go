@@ -0,0 +1,98 @@
+package denoise
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/iFaceless/godub"
+	"github.com/stretchr/testify/assert"
+)
+
+// newNoisyTone creates a second of white noise followed by a second of a 440Hz
+// sine with the same noise, at 16kHz.
+func newNoisyTone(t *testing.T, sampleWidth int) (noisy, clean *godub.AudioSegment) {
+	r := rand.New(rand.NewSource(1))
+	frameRate := 16000
+	noisySamples := make([]float64, 2*frameRate)
+	cleanSamples := make([]float64, 2*frameRate)
+	for i := range noisySamples {
+		if i >= frameRate {
+			cleanSamples[i] = 0.3 * math.Sin(2*math.Pi*440*float64(i)/float64(frameRate))
+		}
+		noisySamples[i] = cleanSamples[i] + 0.02*(r.Float64()-r.Float64())
+	}
+
+	noisy, err := godub.NewAudioSegmentFromChannelFloatSamples([][]float64{noisySamples}, uint32(frameRate))
+	assert.Nil(t, err)
+	noisy, err = noisy.ForkWithSampleFormat(godub.SampleFormatPCM, sampleWidth)
+	assert.Nil(t, err)
+	clean, err = godub.NewAudioSegmentFromChannelFloatSamples([][]float64{cleanSamples}, uint32(frameRate))
+	assert.Nil(t, err)
+	return noisy, clean
+}
+
+func slice(t *testing.T, seg *godub.AudioSegment, start, end time.Duration) *godub.AudioSegment {
+	r, err := seg.Slice(start, end)
+	assert.Nil(t, err)
+	return r
+}
+
+func TestReduce(t *testing.T) {
+	noisy, clean := newNoisyTone(t, 2)
+	noise := slice(t, noisy, 0, 500*time.Millisecond)
+
+	for _, method := range []Method{SpectralSubtraction, Wiener} {
+		reduced, err := Reduce(noisy, noise, &Config{Method: method, Reduction: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, noisy.Duration(), reduced.Duration())
+		assert.Equal(t, godub.SampleFormatPCM, reduced.SampleFormat())
+		assert.Equal(t, uint16(2), reduced.SampleWidth())
+
+		// The noise is attenuated by nearly the whole reduction.
+		before := slice(t, noisy, 200*time.Millisecond, 800*time.Millisecond).DBFS()
+		after := slice(t, reduced, 200*time.Millisecond, 800*time.Millisecond).DBFS()
+		assert.InDelta(t, -20, float64(after-before), 3)
+
+		// The tone is kept.
+		tone := slice(t, clean, 1200*time.Millisecond, 1800*time.Millisecond).DBFS()
+		assert.InDelta(t, float64(tone), float64(slice(t, reduced, 1200*time.Millisecond, 1800*time.Millisecond).DBFS()), 0.5)
+	}
+}
+
+func TestReduceAmount(t *testing.T) {
+	noisy, _ := newNoisyTone(t, 2)
+	profile, err := NewProfile(slice(t, noisy, 0, time.Second))
+	assert.Nil(t, err)
+
+	before := slice(t, noisy, 200*time.Millisecond, 800*time.Millisecond).DBFS()
+	for _, reduction := range []godub.Volume{6, 12} {
+		reduced, err := profile.Reduce(noisy, &Config{Reduction: reduction})
+		assert.Nil(t, err)
+
+		after := slice(t, reduced, 200*time.Millisecond, 800*time.Millisecond).DBFS()
+		assert.True(t, after-before >= -reduction-0.1)
+		assert.True(t, after-before < -reduction+2)
+	}
+}
+
+func TestReduceErrors(t *testing.T) {
+	noisy, _ := newNoisyTone(t, 2)
+
+	_, err := NewProfile(slice(t, noisy, 0, 10*time.Millisecond))
+	assert.Error(t, err)
+
+	profile, err := NewProfile(slice(t, noisy, 0, time.Second))
+	assert.Nil(t, err)
+
+	resampled, err := noisy.ForkWithFrameRate(8000)
+	assert.Nil(t, err)
+	_, err = profile.Reduce(resampled, &Config{})
+	assert.Error(t, err)
+
+	_, err = profile.Reduce(noisy, &Config{Reduction: -6})
+	assert.Error(t, err)
+	_, err = profile.Reduce(noisy, &Config{Method: Method(5)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package denoise
+
+import "math"
+
+// stft holds the parameters of the short-time Fourier transform. Frames overlap by 75%
+// and are windowed both before the transform and after the inverse transform.
+type stft struct {
+	size   int
+	hop    int
+	window []float64
+}
+
+// newSTFT creates a transform with frames of about 32ms at the given frame rate.
+func newSTFT(frameRate uint32) *stft {
+	size := 256
+	for float64(size) < 0.032*float64(frameRate) {
+		size *= 2
+	}
+
+	// Periodic Hann window.
+	window := make([]float64, size)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(size)))
+	}
+	return &stft{size: size, hop: size / 4, window: window}
+}
+
+// bins returns the number of frequency bins of each frame, up to the Nyquist frequency.
+func (s *stft) bins() int {
+	return s.size/2 + 1
+}
+
+// forward returns the spectrum of each frame. Samples are padded with silence, so that
+// every sample is covered by the same number of frames.
+func (s *stft) forward(samples []float64) [][]complex128 {
+	padded := make([]float64, len(samples)+2*s.size)
+	copy(padded[s.size:], samples)
+
+	frames := make([][]complex128, 0, len(padded)/s.hop)
+	buf := make([]complex128, s.size)
+	for start := 0; start+s.size <= len(padded); start += s.hop {
+		for i, w := range s.window {
+			buf[i] = complex(padded[start+i]*w, 0)
+		}
+		fft(buf, false)
+
+		frame := make([]complex128, s.bins())
+		copy(frame, buf)
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// inverse rebuilds `length` samples from the spectrum of each frame, see forward.
+func (s *stft) inverse(frames [][]complex128, length int) []float64 {
+	output := make([]float64, length+2*s.size)
+	weights := make([]float64, len(output))
+	buf := make([]complex128, s.size)
+	for f, frame := range frames {
+		// Rebuild the negative frequencies of the real signal.
+		copy(buf, frame)
+		for i := len(frame); i < s.size; i++ {
+			buf[i] = complex(real(frame[s.size-i]), -imag(frame[s.size-i]))
+		}
+		fft(buf, true)
+
+		start := f * s.hop
+		for i, w := range s.window {
+			if start+i >= len(output) {
+				break
+			}
+			output[start+i] += real(buf[i]) * w
+			weights[start+i] += w * w
+		}
+	}
+
+	result := output[s.size : s.size+length]
+	for i := range result {
+		if w := weights[s.size+i]; w > 1e-9 {
+			result[i] /= w
+		}
+	}
+	return result
+}
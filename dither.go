@@ -0,0 +1,78 @@
+package godub
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/iFaceless/godub/audioop"
+)
+
+// Dither is the noise added when the sample width is reduced, it turns
+// quantisation distortion into a constant, much less audible noise floor.
+type Dither int
+
+const (
+	// DitherNone truncates the samples.
+	DitherNone Dither = iota
+	// DitherTPDF adds triangular probability density noise of +/-1 LSB.
+	DitherTPDF
+	// DitherShaped adds TPDF noise and feeds the quantisation error back,
+	// which moves the noise towards high frequencies where it's less audible.
+	DitherShaped
+)
+
+// ForkWithSampleWidthDither changes the sample width like ForkWithSampleWidth, adding
+// dither when the sample width is reduced. Float audio stays float and isn't dithered,
+// convert it with ForkWithSampleFormat first to reduce it to PCM.
+func (seg *AudioSegment) ForkWithSampleWidthDither(sampleWidth int, dither Dither) (*AudioSegment, error) {
+	if dither == DitherNone || seg.sampleFormat == SampleFormatFloat || sampleWidth >= int(seg.sampleWidth) {
+		return seg.ForkWithSampleWidth(sampleWidth)
+	}
+
+	data, err := seg.signedData()
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(seg.sampleWidth)
+	channels := int(seg.channels)
+	count := len(data) / size
+	if count%channels != 0 {
+		return nil, NewAudioSegmentError("not a whole number of frames")
+	}
+
+	// Step is the size of one LSB of the new sample width, in the units of the current one.
+	step := math.Pow(2, float64(8*(size-sampleWidth)))
+	maxValue := math.Pow(2, float64(8*sampleWidth-1)) - 1
+	minValue := -maxValue - 1
+
+	buf := make([]byte, count*sampleWidth)
+	feedback := make([]float64, channels)
+	for i := 0; i < count; i++ {
+		sample, err := audioop.GetSample(data, size, i)
+		if err != nil {
+			return nil, err
+		}
+
+		c := i % channels
+		value := float64(sample)/step - feedback[c]
+		noise := rand.Float64() - rand.Float64()
+		quantized := math.Max(minValue, math.Min(maxValue, math.Round(value+noise)))
+		if dither == DitherShaped {
+			feedback[c] = quantized - value
+		}
+
+		sample = int32(quantized)
+		if sampleWidth == 1 {
+			sample = int32(int8(uint8(sample + 128)))
+		}
+
+		err = audioop.PutSample(buf, sampleWidth, i, sample)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	frameWidth := channels * sampleWidth
+	return seg.derive(buf, SampleWidth(uint16(sampleWidth)), FrameWidth(uint32(frameWidth)))
+}
@@ -0,0 +1,107 @@
+package godub
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newConstantSegment returns a mono segment whose samples all have `value`.
+func newConstantSegment(t *testing.T, value float64, duration time.Duration, sampleWidth uint16) *AudioSegment {
+	seg := &AudioSegment{sampleWidth: sampleWidth, frameRate: 8000, channels: 1}
+	samples := make([]float64, int(duration.Seconds()*8000))
+	for i := range samples {
+		samples[i] = value
+	}
+
+	ret, err := seg.deriveFromChannelSamples([][]float64{samples})
+	assert.Nil(t, err)
+	return ret
+}
+
+func TestForkWithSampleWidthDither(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 2)
+
+	for _, dither := range []Dither{DitherNone, DitherTPDF, DitherShaped} {
+		reduced, err := seg.ForkWithSampleWidthDither(1, dither)
+		assert.Nil(t, err)
+		assert.Equal(t, uint16(1), reduced.SampleWidth())
+		assert.Equal(t, uint32(2), reduced.FrameWidth())
+		assert.Equal(t, seg.Duration(), reduced.Duration())
+		assert.InDelta(t, float64(seg.DBFS()), float64(reduced.DBFS()), 0.2)
+	}
+
+	// Increasing the sample width doesn't need dither.
+	widened, err := seg.ForkWithSampleWidthDither(4, DitherTPDF)
+	assert.Nil(t, err)
+	expected, err := seg.ForkWithSampleWidth(4)
+	assert.Nil(t, err)
+	assert.True(t, expected.Equal(widened))
+
+	// Float audio keeps its format, like with ForkWithSampleWidth.
+	float, err := seg.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+	double, err := float.ForkWithSampleWidthDither(8, DitherTPDF)
+	assert.Nil(t, err)
+	assert.Equal(t, SampleFormatFloat, double.SampleFormat())
+	for _, dither := range []Dither{DitherNone, DitherTPDF} {
+		_, err = float.ForkWithSampleWidthDither(2, dither)
+		assert.Error(t, err)
+	}
+}
+
+func TestDitherKeepsQuietSignal(t *testing.T) {
+	// A quarter of the smallest step of 8-bit audio.
+	value := 0.25 / 128
+	seg := newConstantSegment(t, value, time.Second, 2)
+
+	truncated, err := seg.ForkWithSampleWidthDither(1, DitherNone)
+	assert.Nil(t, err)
+	samples, err := truncated.channelSamples()
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, mean(samples[0]))
+
+	for _, dither := range []Dither{DitherTPDF, DitherShaped} {
+		dithered, err := seg.ForkWithSampleWidthDither(1, dither)
+		assert.Nil(t, err)
+		samples, err := dithered.channelSamples()
+		assert.Nil(t, err)
+		assert.InDelta(t, value, mean(samples[0]), 0.05/128)
+	}
+}
+
+func TestDitherShapedMovesNoiseUp(t *testing.T) {
+	seg := newConstantSegment(t, 0.25/128, time.Second, 2)
+
+	// The error summed over a window only keeps the low frequencies of the noise.
+	lowFrequencyNoise := func(dither Dither) float64 {
+		dithered, err := seg.ForkWithSampleWidthDither(1, dither)
+		assert.Nil(t, err)
+		samples, err := dithered.channelSamples()
+		assert.Nil(t, err)
+
+		var sum float64
+		windows := 0
+		for i := 0; i+64 <= len(samples[0]); i += 64 {
+			var windowError float64
+			for _, v := range samples[0][i : i+64] {
+				windowError += v*128 - 0.25
+			}
+			sum += windowError * windowError
+			windows++
+		}
+		return math.Sqrt(sum / float64(windows))
+	}
+
+	assert.True(t, lowFrequencyNoise(DitherShaped) < lowFrequencyNoise(DitherTPDF)/2)
+}
+
+func mean(samples []float64) float64 {
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	return sum / float64(len(samples))
+}
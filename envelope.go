@@ -0,0 +1,87 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+// envelopeFollower smooths a signal, rising with the attack time
+// and falling with the release time.
+type envelopeFollower struct {
+	attackCoef  float64
+	releaseCoef float64
+	value       float64
+}
+
+func newEnvelopeFollower(attack, release time.Duration, frameRate uint32, initial float64) *envelopeFollower {
+	return &envelopeFollower{
+		attackCoef:  timeCoefficient(attack, frameRate),
+		releaseCoef: timeCoefficient(release, frameRate),
+		value:       initial,
+	}
+}
+
+// next moves the envelope one frame towards target and returns the new value.
+func (e *envelopeFollower) next(target float64) float64 {
+	coef := e.releaseCoef
+	if target > e.value {
+		coef = e.attackCoef
+	}
+
+	e.value = target + coef*(e.value-target)
+	return e.value
+}
+
+// timeCoefficient returns the one-pole smoothing coefficient for the given time constant.
+func timeCoefficient(d time.Duration, frameRate uint32) float64 {
+	if d <= 0 || frameRate == 0 {
+		return 0
+	}
+	return math.Exp(-1 / (d.Seconds() * float64(frameRate)))
+}
+
+// prefixSumOfSquares returns the running sum of squared samples of all channels,
+// the sum of frames [i, j) is result[j] - result[i].
+func prefixSumOfSquares(samples [][]float64) []float64 {
+	frameCount := 0
+	if len(samples) > 0 {
+		frameCount = len(samples[0])
+	}
+
+	result := make([]float64, frameCount+1)
+	for i := 0; i < frameCount; i++ {
+		var sum float64
+		for _, channel := range samples {
+			sum += channel[i] * channel[i]
+		}
+		result[i+1] = result[i] + sum
+	}
+	return result
+}
+
+// windowedLevels returns the RMS of all channels in a window of `window` frames
+// ending at each frame, so that channels are linked together.
+func windowedLevels(samples [][]float64, window int) []float64 {
+	if window < 1 {
+		window = 1
+	}
+
+	sums := prefixSumOfSquares(samples)
+	levels := make([]float64, len(sums)-1)
+	for i := range levels {
+		start := i + 1 - window
+		if start < 0 {
+			start = 0
+		}
+
+		count := float64((i + 1 - start) * len(samples))
+		levels[i] = math.Sqrt(math.Max(0, sums[i+1]-sums[start]) / count)
+	}
+	return levels
+}
+
+// ratioToDB converts an amplitude ratio to decibels, the result is
+// bounded at -200dB for silence.
+func ratioToDB(ratio float64) float64 {
+	return 20 * math.Log10(math.Max(ratio, 1e-10))
+}
@@ -11,15 +11,32 @@ import (
 )
 
 type Exporter struct {
-	converter *converter.Converter
-	dst       interface{}
+	converter   *converter.Converter
+	dst         interface{}
+	sampleWidth int
+	dither      Dither
 }
 
 func NewExporter(dst interface{}) *Exporter {
-	return &Exporter{converter: converter.NewConverter(nil), dst: dst}
+	return &Exporter{converter: converter.NewConverter(nil), dst: dst, dither: DitherTPDF}
 }
 
 func (e *Exporter) Export(segment *AudioSegment) error {
+	if e.sampleWidth > 0 && (e.sampleWidth != int(segment.sampleWidth) || segment.sampleFormat == SampleFormatFloat) {
+		var err error
+		if segment.sampleFormat == SampleFormatFloat {
+			segment, err = segment.ForkWithSampleFormat(SampleFormatPCM, 4)
+			if err != nil {
+				return err
+			}
+		}
+
+		segment, err = segment.ForkWithSampleWidthDither(e.sampleWidth, e.dither)
+		if err != nil {
+			return err
+		}
+	}
+
 	wavBuf := bytes.Buffer{}
 	err := wav.Encode(&wavBuf, segment.AsWaveAudio())
 	if err != nil {
@@ -68,6 +85,19 @@ func (e *Exporter) WithSampleRate(rate int) *Exporter {
 	return e
 }
 
+// WithSampleWidth sets the sample width of the exported audio, in bytes. Float audio is exported
+// as PCM when it's set.
+func (e *Exporter) WithSampleWidth(w int) *Exporter {
+	e.sampleWidth = w
+	return e
+}
+
+// WithDither sets the dither used when the sample width is reduced, DitherTPDF by default.
+func (e *Exporter) WithDither(d Dither) *Exporter {
+	e.dither = d
+	return e
+}
+
 func (e *Exporter) WithTags(tags map[string]string) *Exporter {
 	e.converter.WithTags(tags)
 	return e
@@ -0,0 +1,104 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+// FadeCurve describes how the gain changes over the course of a fade.
+type FadeCurve int
+
+const (
+	// FadeLinear changes the amplitude at a constant rate.
+	FadeLinear FadeCurve = iota
+	// FadeLogarithmic changes quickly at first and slows down towards the end.
+	FadeLogarithmic
+	// FadeExponential changes slowly at first and speeds up towards the end.
+	FadeExponential
+	// FadeSCurve eases in and out, changing fastest in the middle.
+	FadeSCurve
+	// FadeEqualPower follows a quarter sine, so that two crossfaded
+	// uncorrelated signals keep a constant power.
+	FadeEqualPower
+)
+
+// shape maps the progress of a fade (0 to 1) to the fraction of the gain change applied.
+func (curve FadeCurve) shape(progress float64) float64 {
+	progress = math.Max(0, math.Min(1, progress))
+
+	switch curve {
+	case FadeLogarithmic:
+		return math.Log10(1 + 9*progress)
+	case FadeExponential:
+		return (math.Pow(10, progress) - 1) / 9
+	case FadeSCurve:
+		return (1 - math.Cos(math.Pi*progress)) / 2
+	case FadeEqualPower:
+		return math.Sin(math.Pi / 2 * progress)
+	default:
+		return progress
+	}
+}
+
+// Fade changes the volume from `from` to `to` between `start` and `end`.
+// Audio before `start` gets the `from` gain, audio after `end` gets the `to` gain.
+func (seg *AudioSegment) Fade(from, to Volume, start, end time.Duration, curve FadeCurve) (*AudioSegment, error) {
+	if start > end {
+		return nil, NewAudioSegmentError("start should be smaller than end")
+	}
+
+	if start < 0 || end < 0 {
+		return nil, NewAudioSegmentError("start or end should be positive")
+	}
+
+	audioLength := seg.Duration()
+	if start > audioLength {
+		start = audioLength
+	}
+
+	if end > audioLength {
+		end = audioLength
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	fromRatio, toRatio := from.ToRatio(true), to.ToRatio(true)
+	startFrame, endFrame := seg.parsePosition(start), seg.parsePosition(end)
+	fadeFrames := endFrame - startFrame
+
+	for _, channel := range samples {
+		for i := range channel {
+			var ratio float64
+			switch {
+			case i < startFrame:
+				ratio = fromRatio
+			case i >= endFrame:
+				ratio = toRatio
+			default:
+				progress := float64(i-startFrame) / float64(fadeFrames)
+				ratio = fromRatio + (toRatio-fromRatio)*curve.shape(progress)
+			}
+			channel[i] *= ratio
+		}
+	}
+
+	return seg.deriveFromChannelSamples(samples)
+}
+
+// FadeIn fades in from silence over the first `duration` of the segment.
+func (seg *AudioSegment) FadeIn(duration time.Duration) (*AudioSegment, error) {
+	return seg.Fade(SilentVolume, 0, 0, duration, FadeLinear)
+}
+
+// FadeOut fades out to silence over the last `duration` of the segment.
+func (seg *AudioSegment) FadeOut(duration time.Duration) (*AudioSegment, error) {
+	end := seg.Duration()
+	start := end - duration
+	if start < 0 {
+		start = 0
+	}
+	return seg.Fade(0, SilentVolume, start, end, FadeLinear)
+}
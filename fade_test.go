@@ -0,0 +1,45 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFadeIn(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 2, 2)
+
+	faded, err := seg.FadeIn(500 * time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, seg.Duration(), faded.Duration())
+
+	head, _ := faded.Slice(0, 100*time.Millisecond)
+	tail, _ := faded.Slice(500*time.Millisecond, time.Second)
+	origTail, _ := seg.Slice(500*time.Millisecond, time.Second)
+	assert.True(t, head.RMS() < origTail.RMS()/4)
+	assert.True(t, tail.Equal(origTail))
+}
+
+func TestFadeOut(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 1, 2)
+
+	faded, err := seg.FadeOut(200 * time.Millisecond)
+	assert.Nil(t, err)
+
+	last, _ := faded.Slice(990*time.Millisecond, time.Second)
+	assert.True(t, last.Max() < seg.Max()/10)
+}
+
+func TestFadeCurves(t *testing.T) {
+	for _, curve := range []FadeCurve{FadeLinear, FadeLogarithmic, FadeExponential, FadeSCurve} {
+		assert.InDelta(t, 0, curve.shape(0), 1e-9)
+		assert.InDelta(t, 1, curve.shape(1), 1e-9)
+	}
+	assert.True(t, FadeLogarithmic.shape(0.5) > FadeLinear.shape(0.5))
+	assert.True(t, FadeExponential.shape(0.5) < FadeLinear.shape(0.5))
+
+	seg := newSineSegment(t, 440, 0.5, time.Second, 1, 2)
+	_, err := seg.Fade(0, -6, 500*time.Millisecond, 100*time.Millisecond, FadeSCurve)
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package godub
+
+import "github.com/iFaceless/godub/filter"
+
+// ApplyFilter runs the filter over every channel separately.
+func (seg *AudioSegment) ApplyFilter(f filter.Filter) (*AudioSegment, error) {
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	for c, channel := range samples {
+		samples[c] = f.Process(channel)
+	}
+	return seg.deriveFromChannelSamples(samples)
+}
+
+// LowPassFilter cuts frequencies above `cutoff` Hz with a Butterworth low-pass filter.
+func (seg *AudioSegment) LowPassFilter(cutoff float64) (*AudioSegment, error) {
+	f, err := filter.NewLowPass(float64(seg.frameRate), cutoff, filter.ButterworthQ)
+	if err != nil {
+		return nil, err
+	}
+	return seg.ApplyFilter(f)
+}
+
+// HighPassFilter cuts frequencies below `cutoff` Hz with a Butterworth high-pass filter.
+func (seg *AudioSegment) HighPassFilter(cutoff float64) (*AudioSegment, error) {
+	f, err := filter.NewHighPass(float64(seg.frameRate), cutoff, filter.ButterworthQ)
+	if err != nil {
+		return nil, err
+	}
+	return seg.ApplyFilter(f)
+}
+
+// ApplyParametricEQ applies all bands of the equalizer in a single pass.
+func (seg *AudioSegment) ApplyParametricEQ(eq *filter.ParametricEQ) (*AudioSegment, error) {
+	cascade, err := eq.Filter(float64(seg.frameRate))
+	if err != nil {
+		return nil, err
+	}
+	return seg.ApplyFilter(cascade)
+}
@@ -0,0 +1,159 @@
+package filter
+
+import "math"
+
+// ButterworthQ is the Q of a second order Butterworth filter, which has the flattest passband.
+const ButterworthQ = 1 / math.Sqrt2
+
+// Filter processes one channel of samples and returns the filtered samples.
+type Filter interface {
+	Process(samples []float64) []float64
+}
+
+// Biquad is a second order IIR filter, the coefficients are normalised so that a0 is 1.
+type Biquad struct {
+	B0, B1, B2 float64
+	A1, A2     float64
+}
+
+// biquadState holds the delayed values of the transposed direct form II.
+type biquadState struct {
+	z1, z2 float64
+}
+
+func (b *Biquad) tick(state *biquadState, x float64) float64 {
+	y := b.B0*x + state.z1
+	state.z1 = b.B1*x - b.A1*y + state.z2
+	state.z2 = b.B2*x - b.A2*y
+	return y
+}
+
+// Process filters the samples, starting from a silent state.
+func (b *Biquad) Process(samples []float64) []float64 {
+	var state biquadState
+	result := make([]float64, len(samples))
+	for i, x := range samples {
+		result[i] = b.tick(&state, x)
+	}
+	return result
+}
+
+// NewLowPass creates a low-pass filter, which cuts frequencies above `freq`.
+func NewLowPass(sampleRate, freq, q float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	cos := math.Cos(w0)
+	return newBiquad((1-cos)/2, 1-cos, (1-cos)/2, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewHighPass creates a high-pass filter, which cuts frequencies below `freq`.
+func NewHighPass(sampleRate, freq, q float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	cos := math.Cos(w0)
+	return newBiquad((1+cos)/2, -(1 + cos), (1+cos)/2, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewBandPass creates a band-pass filter centered on `freq` with 0dB peak gain,
+// a higher `q` gives a narrower band.
+func NewBandPass(sampleRate, freq, q float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	cos := math.Cos(w0)
+	return newBiquad(alpha, 0, -alpha, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewNotch creates a notch filter, which removes a narrow band around `freq`.
+func NewNotch(sampleRate, freq, q float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	cos := math.Cos(w0)
+	return newBiquad(1, -2*cos, 1, 1+alpha, -2*cos, 1-alpha), nil
+}
+
+// NewPeaking creates a peaking filter, which boosts or cuts a band around `freq` by `gain` dB.
+func NewPeaking(sampleRate, freq, q, gain float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	a := math.Pow(10, gain/40)
+	cos := math.Cos(w0)
+	return newBiquad(1+alpha*a, -2*cos, 1-alpha*a, 1+alpha/a, -2*cos, 1-alpha/a), nil
+}
+
+// NewLowShelf creates a low-shelf filter, which boosts or cuts frequencies below `freq` by `gain` dB.
+func NewLowShelf(sampleRate, freq, q, gain float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	a := math.Pow(10, gain/40)
+	cos := math.Cos(w0)
+	sqrtA := 2 * math.Sqrt(a) * alpha
+	return newBiquad(
+		a*((a+1)-(a-1)*cos+sqrtA),
+		2*a*((a-1)-(a+1)*cos),
+		a*((a+1)-(a-1)*cos-sqrtA),
+		(a+1)+(a-1)*cos+sqrtA,
+		-2*((a-1)+(a+1)*cos),
+		(a+1)+(a-1)*cos-sqrtA,
+	), nil
+}
+
+// NewHighShelf creates a high-shelf filter, which boosts or cuts frequencies above `freq` by `gain` dB.
+func NewHighShelf(sampleRate, freq, q, gain float64) (*Biquad, error) {
+	w0, alpha, err := prepare(sampleRate, freq, q)
+	if err != nil {
+		return nil, err
+	}
+
+	a := math.Pow(10, gain/40)
+	cos := math.Cos(w0)
+	sqrtA := 2 * math.Sqrt(a) * alpha
+	return newBiquad(
+		a*((a+1)+(a-1)*cos+sqrtA),
+		-2*a*((a-1)+(a+1)*cos),
+		a*((a+1)+(a-1)*cos-sqrtA),
+		(a+1)-(a-1)*cos+sqrtA,
+		2*((a-1)-(a+1)*cos),
+		(a+1)-(a-1)*cos-sqrtA,
+	), nil
+}
+
+// newBiquad normalises the coefficients by a0.
+func newBiquad(b0, b1, b2, a0, a1, a2 float64) *Biquad {
+	return &Biquad{B0: b0 / a0, B1: b1 / a0, B2: b2 / a0, A1: a1 / a0, A2: a2 / a0}
+}
+
+// prepare checks the parameters and returns the angular frequency and alpha.
+func prepare(sampleRate, freq, q float64) (float64, float64, error) {
+	if sampleRate <= 0 {
+		return 0, 0, NewError("sample rate should be > 0")
+	}
+
+	if freq <= 0 || freq >= sampleRate/2 {
+		return 0, 0, NewError("frequency should be between 0 and %.0fHz (Nyquist)", sampleRate/2)
+	}
+
+	if q <= 0 {
+		return 0, 0, NewError("q should be > 0")
+	}
+
+	w0 := 2 * math.Pi * freq / sampleRate
+	return w0, math.Sin(w0) / (2 * q), nil
+}
@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gainAt returns the steady state gain of the filter for a sine wave at `freq`.
+func gainAt(f Filter, sampleRate, freq float64) float64 {
+	samples := make([]float64, int(sampleRate))
+	for i := range samples {
+		samples[i] = math.Sin(2 * math.Pi * freq * float64(i) / sampleRate)
+	}
+
+	filtered := f.Process(samples)
+	var peak float64
+	for _, v := range filtered[len(filtered)/2:] {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	return 20 * math.Log10(peak)
+}
+
+func TestLowPassAndHighPass(t *testing.T) {
+	lowPass, err := NewLowPass(44100, 1000, ButterworthQ)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, gainAt(lowPass, 44100, 100), 0.1)
+	assert.InDelta(t, -3, gainAt(lowPass, 44100, 1000), 0.1)
+	assert.True(t, gainAt(lowPass, 44100, 10000) < -35)
+
+	highPass, err := NewHighPass(44100, 1000, ButterworthQ)
+	assert.Nil(t, err)
+	assert.True(t, gainAt(highPass, 44100, 100) < -35)
+	assert.InDelta(t, 0, gainAt(highPass, 44100, 10000), 0.1)
+}
+
+func TestPeakingAndShelves(t *testing.T) {
+	peaking, err := NewPeaking(48000, 1000, 1, 6)
+	assert.Nil(t, err)
+	assert.InDelta(t, 6, gainAt(peaking, 48000, 1000), 0.1)
+	assert.InDelta(t, 0, gainAt(peaking, 48000, 15000), 0.2)
+
+	lowShelf, err := NewLowShelf(48000, 200, ButterworthQ, -6)
+	assert.Nil(t, err)
+	assert.InDelta(t, -6, gainAt(lowShelf, 48000, 20), 0.2)
+	assert.InDelta(t, 0, gainAt(lowShelf, 48000, 10000), 0.1)
+
+	highShelf, err := NewHighShelf(48000, 5000, ButterworthQ, 6)
+	assert.Nil(t, err)
+	assert.InDelta(t, 6, gainAt(highShelf, 48000, 20000), 0.2)
+	assert.InDelta(t, 0, gainAt(highShelf, 48000, 100), 0.1)
+
+	notch, err := NewNotch(48000, 1000, 10)
+	assert.Nil(t, err)
+	assert.True(t, gainAt(notch, 48000, 1000) < -30)
+
+	bandPass, err := NewBandPass(48000, 1000, 2)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, gainAt(bandPass, 48000, 1000), 0.1)
+}
+
+func TestInvalidParameters(t *testing.T) {
+	_, err := NewLowPass(8000, 4000, ButterworthQ)
+	assert.Error(t, err)
+
+	_, err = NewHighPass(0, 100, ButterworthQ)
+	assert.Error(t, err)
+
+	_, err = NewBandPass(8000, 100, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,4 @@
+// Package filter provides digital filters that process one channel of normalised samples.
+// The biquad filters are based on the Audio EQ Cookbook by Robert Bristow-Johnson:
+//  1. https://www.w3.org/TR/audio-eq-cookbook/
+package filter
@@ -0,0 +1,90 @@
+package filter
+
+// BandType is the type of filter used by an equalizer band.
+type BandType string
+
+const (
+	BandLowPass   BandType = "lowpass"
+	BandHighPass  BandType = "highpass"
+	BandBandPass  BandType = "bandpass"
+	BandNotch     BandType = "notch"
+	BandPeaking   BandType = "peaking"
+	BandLowShelf  BandType = "lowshelf"
+	BandHighShelf BandType = "highshelf"
+)
+
+// Band is a single band of a parametric equalizer.
+type Band struct {
+	Type BandType `json:"type"`
+	// Frequency is the center or cutoff frequency in Hz.
+	Frequency float64 `json:"frequency"`
+	// Gain in dB, only used by peaking and shelving bands.
+	Gain float64 `json:"gain,omitempty"`
+	// Q controls the bandwidth, default to ButterworthQ.
+	Q float64 `json:"q,omitempty"`
+}
+
+// Biquad creates the filter of the band for the given sample rate.
+func (b Band) Biquad(sampleRate float64) (*Biquad, error) {
+	q := b.Q
+	if q == 0 {
+		q = ButterworthQ
+	}
+
+	switch b.Type {
+	case BandLowPass:
+		return NewLowPass(sampleRate, b.Frequency, q)
+	case BandHighPass:
+		return NewHighPass(sampleRate, b.Frequency, q)
+	case BandBandPass:
+		return NewBandPass(sampleRate, b.Frequency, q)
+	case BandNotch:
+		return NewNotch(sampleRate, b.Frequency, q)
+	case BandPeaking:
+		return NewPeaking(sampleRate, b.Frequency, q, b.Gain)
+	case BandLowShelf:
+		return NewLowShelf(sampleRate, b.Frequency, q, b.Gain)
+	case BandHighShelf:
+		return NewHighShelf(sampleRate, b.Frequency, q, b.Gain)
+	default:
+		return nil, NewError("unknown band type: %q", b.Type)
+	}
+}
+
+// ParametricEQ is a list of bands applied one after another. It can be
+// stored as JSON, e.g. {"bands": [{"type": "peaking", "frequency": 1000, "gain": 3, "q": 1.4}]}.
+type ParametricEQ struct {
+	Bands []Band `json:"bands"`
+}
+
+func NewParametricEQ(bands ...Band) *ParametricEQ {
+	return &ParametricEQ{Bands: bands}
+}
+
+// Filter creates a filter of all bands for the given sample rate.
+func (eq *ParametricEQ) Filter(sampleRate float64) (Cascade, error) {
+	cascade := make(Cascade, 0, len(eq.Bands))
+	for _, band := range eq.Bands {
+		b, err := band.Biquad(sampleRate)
+		if err != nil {
+			return nil, err
+		}
+		cascade = append(cascade, b)
+	}
+	return cascade, nil
+}
+
+// Cascade chains biquads, every sample goes through all of them in a single pass.
+type Cascade []*Biquad
+
+func (c Cascade) Process(samples []float64) []float64 {
+	states := make([]biquadState, len(c))
+	result := make([]float64, len(samples))
+	for i, x := range samples {
+		for j, b := range c {
+			x = b.tick(&states[j], x)
+		}
+		result[i] = x
+	}
+	return result
+}
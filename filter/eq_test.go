@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParametricEQ(t *testing.T) {
+	preset := `{"bands": [
+		{"type": "highpass", "frequency": 80},
+		{"type": "peaking", "frequency": 1000, "gain": 6, "q": 1},
+		{"type": "highshelf", "frequency": 8000, "gain": -3}
+	]}`
+
+	var eq ParametricEQ
+	assert.Nil(t, json.Unmarshal([]byte(preset), &eq))
+	assert.Len(t, eq.Bands, 3)
+	assert.Equal(t, BandPeaking, eq.Bands[1].Type)
+
+	cascade, err := eq.Filter(48000)
+	assert.Nil(t, err)
+	assert.Len(t, cascade, 3)
+	assert.True(t, gainAt(cascade, 48000, 20) < -20)
+	assert.InDelta(t, 6, gainAt(cascade, 48000, 1000), 0.2)
+	assert.InDelta(t, -3, gainAt(cascade, 48000, 20000), 0.3)
+
+	encoded, err := json.Marshal(NewParametricEQ(Band{Type: BandNotch, Frequency: 50, Q: 10}))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"bands":[{"type":"notch","frequency":50,"q":10}]}`, string(encoded))
+
+	_, err = NewParametricEQ(Band{Type: "unknown", Frequency: 50}).Filter(48000)
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iFaceless/godub/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLowPassAndHighPassFilter(t *testing.T) {
+	low := newSineSegment(t, 100, 0.5, time.Second, 2, 2)
+	high := newSineSegment(t, 3000, 0.5, time.Second, 2, 2)
+	mix, _ := low.Overlay(high, &OverlayConfig{})
+
+	filtered, err := mix.LowPassFilter(500)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(low.DBFS()), float64(filtered.DBFS()), 0.5)
+
+	filtered, err = mix.HighPassFilter(1000)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(high.DBFS()), float64(filtered.DBFS()), 0.5)
+
+	_, err = mix.LowPassFilter(5000)
+	assert.Error(t, err)
+}
+
+func TestApplyParametricEQ(t *testing.T) {
+	seg := newSineSegment(t, 1000, 0.25, time.Second, 1, 2)
+	eq := filter.NewParametricEQ(filter.Band{Type: filter.BandPeaking, Frequency: 1000, Gain: 6, Q: 2})
+
+	equalized, err := seg.ApplyParametricEQ(eq)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(seg.DBFS())+6, float64(equalized.DBFS()), 0.3)
+}
@@ -0,0 +1,72 @@
+package godub
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// SampleFormat is the encoding of the samples.
+type SampleFormat int
+
+const (
+	// SampleFormatPCM stores samples as signed integers, except 8-bit samples which are unsigned.
+	SampleFormatPCM SampleFormat = iota
+	// SampleFormatFloat stores samples as 32-bit or 64-bit IEEE floats, full scale is 1.
+	SampleFormatFloat
+)
+
+func (f SampleFormat) String() string {
+	if f == SampleFormatFloat {
+		return "float"
+	}
+	return "pcm"
+}
+
+// ForkWithSampleFormat converts the audio to the given sample format and width. Float
+// samples should be 4 or 8 bytes wide, float samples out of the [-1, 1] range are clipped
+// when converted to PCM.
+func (seg *AudioSegment) ForkWithSampleFormat(format SampleFormat, sampleWidth int) (*AudioSegment, error) {
+	if format == SampleFormatFloat && sampleWidth != 4 && sampleWidth != 8 {
+		return nil, NewAudioSegmentError("float samples should be 4 or 8 bytes wide, got %d", sampleWidth)
+	}
+
+	if format == seg.sampleFormat && sampleWidth == int(seg.sampleWidth) {
+		return seg, nil
+	}
+
+	// Integer conversions are bit-exact.
+	if format == SampleFormatPCM && seg.sampleFormat == SampleFormatPCM {
+		return seg.ForkWithSampleWidth(sampleWidth)
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+	return seg.deriveFromChannelSamples(samples, Format(format), SampleWidth(uint16(sampleWidth)))
+}
+
+func getFloatSample(data []byte, size int, offset int) (float64, error) {
+	start := offset * size
+	switch size {
+	case 4:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data[start:]))), nil
+	case 8:
+		return math.Float64frombits(binary.LittleEndian.Uint64(data[start:])), nil
+	default:
+		return 0, NewAudioSegmentError("float samples should be 4 or 8 bytes wide, got %d", size)
+	}
+}
+
+func putFloatSample(data []byte, size int, offset int, value float64) error {
+	start := offset * size
+	switch size {
+	case 4:
+		binary.LittleEndian.PutUint32(data[start:], math.Float32bits(float32(value)))
+	case 8:
+		binary.LittleEndian.PutUint64(data[start:], math.Float64bits(value))
+	default:
+		return NewAudioSegmentError("float samples should be 4 or 8 bytes wide, got %d", size)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package godub
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/iFaceless/godub/wav"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkWithSampleFormat(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 2)
+
+	for _, width := range []int{4, 8} {
+		float, err := seg.ForkWithSampleFormat(SampleFormatFloat, width)
+		assert.Nil(t, err)
+		assert.Equal(t, SampleFormatFloat, float.SampleFormat())
+		assert.Equal(t, uint16(width), float.SampleWidth())
+		assert.Equal(t, uint32(2*width), float.FrameWidth())
+		assert.Equal(t, 1.0, float.MaxPossibleAmplitude())
+		assert.Equal(t, seg.Duration(), float.Duration())
+		assert.InDelta(t, float64(seg.DBFS()), float64(float.DBFS()), 0.01)
+		assert.InDelta(t, float64(seg.MaxDBFS()), float64(float.MaxDBFS()), 0.01)
+
+		pcm, err := float.ForkWithSampleFormat(SampleFormatPCM, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, SampleFormatPCM, pcm.SampleFormat())
+		assert.True(t, seg.Equal(pcm))
+	}
+
+	float, err := seg.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+	double, err := float.ForkWithSampleWidth(8)
+	assert.Nil(t, err)
+	assert.Equal(t, SampleFormatFloat, double.SampleFormat())
+	assert.Equal(t, uint16(8), double.SampleWidth())
+
+	_, err = seg.ForkWithSampleFormat(SampleFormatFloat, 2)
+	assert.Error(t, err)
+	_, err = float.ForkWithSampleWidth(2)
+	assert.Error(t, err)
+}
+
+func TestFloatHeadroom(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+	float, err := seg.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+
+	// Float samples go above full scale without clipping.
+	louder, err := float.ApplyGain(Volume(12))
+	assert.Nil(t, err)
+	assert.True(t, louder.Max() > 1.9)
+	quieter, err := louder.ApplyGain(Volume(-12))
+	assert.Nil(t, err)
+	assert.InDelta(t, float.Max(), quieter.Max(), 1e-6)
+
+	// Converting to PCM clips.
+	pcm, err := louder.ForkWithSampleFormat(SampleFormatPCM, 2)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, float64(pcm.MaxDBFS()), 0.01)
+}
+
+func TestFloatOperations(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 1, 2)
+	float, err := seg.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+
+	// Mixing PCM with float audio gives float audio.
+	overlaid, err := seg.Overlay(float, &OverlayConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, SampleFormatFloat, overlaid.SampleFormat())
+	assert.InDelta(t, 2*float.Max(), overlaid.Max(), 1e-6)
+
+	appended, err := seg.Append(float)
+	assert.Nil(t, err)
+	assert.Equal(t, SampleFormatFloat, appended.SampleFormat())
+	assert.Equal(t, 2*time.Second, appended.Duration())
+
+	stereo, err := float.ForkWithChannels(2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(2), stereo.Channels())
+	assert.InDelta(t, float64(float.DBFS()), float64(stereo.DBFS()), 0.01)
+
+	resampled, err := float.ForkWithFrameRateQuality(16000, ResampleLinear)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(16000), resampled.FrameRate())
+	assert.InDelta(t, 440, estimateFrequency(t, resampled), 2)
+
+	reversed, err := float.Reverse()
+	assert.Nil(t, err)
+	twice, err := reversed.Reverse()
+	assert.Nil(t, err)
+	assert.True(t, float.Equal(twice))
+}
+
+func TestFloatWaveRoundTrip(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 2)
+
+	for _, width := range []int{4, 8} {
+		float, err := seg.ForkWithSampleFormat(SampleFormatFloat, width)
+		assert.Nil(t, err)
+
+		buf := bytes.Buffer{}
+		assert.Nil(t, wav.Encode(&buf, float.AsWaveAudio()))
+		waveAudio, err := wav.Decode(&buf)
+		assert.Nil(t, err)
+		assert.Equal(t, uint16(wav.AudioFormatIEEEFloat), waveAudio.Format)
+
+		loaded, err := NewAudioSegmentFromWaveAudio(waveAudio)
+		assert.Nil(t, err)
+		assert.Equal(t, SampleFormatFloat, loaded.SampleFormat())
+		assert.True(t, float.Equal(loaded))
+	}
+}
+
+func TestDecodeExtensibleFloat(t *testing.T) {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint32(data, math.Float32bits(0.5))
+	binary.LittleEndian.PutUint32(data[4:], math.Float32bits(-0.25))
+
+	buf := bytes.Buffer{}
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+40+8+len(data)))
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []interface{}{
+		uint32(40), uint16(wav.AudioFormatExtensible), uint16(1), uint32(8000), uint32(32000),
+		uint16(4), uint16(32), uint16(22), uint16(32), uint32(4),
+	} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.Write([]byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71})
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+
+	waveAudio, err := wav.Decode(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(wav.AudioFormatIEEEFloat), waveAudio.Format)
+
+	seg, err := NewAudioSegmentFromWaveAudio(waveAudio)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, seg.Max())
+}
@@ -0,0 +1,122 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+type GateConfig struct {
+	// Threshold is the level under which the gate closes, default to -40dBFS when nil.
+	Threshold *Volume
+	// Attack is how fast the gate opens when the level goes over the threshold, default to 1ms.
+	Attack time.Duration
+	// Hold is how long the gate stays open after the level falls under the threshold,
+	// so that it doesn't chatter between words. Default to 50ms.
+	Hold time.Duration
+	// Release is how fast the gate closes once the hold time is over, default to 100ms.
+	Release time.Duration
+	// Range is the gain applied when the gate is closed, default to -80dB. Use SilentVolume
+	// to mute completely.
+	Range Volume
+	// Ratio turns the gate into a downward expander when > 1: every dB under the threshold
+	// comes out as `Ratio` dB under it, down to `Range`. 0 means a gate.
+	Ratio float64
+	// Window is the length of the RMS window used to detect levels, default to 10ms.
+	Window time.Duration
+}
+
+func (config GateConfig) withDefaults() GateConfig {
+	if config.Threshold == nil {
+		config.Threshold = VolumePtr(-40)
+	}
+
+	if config.Attack == 0 {
+		config.Attack = time.Millisecond
+	}
+
+	if config.Hold == 0 {
+		config.Hold = 50 * time.Millisecond
+	}
+
+	if config.Release == 0 {
+		config.Release = 100 * time.Millisecond
+	}
+
+	if config.Range == 0 {
+		config.Range = -80
+	}
+
+	if config.Window == 0 {
+		config.Window = 10 * time.Millisecond
+	}
+	return config
+}
+
+// closedGain returns the gain (in dB, never positive) applied to a signal at `level` dB
+// under the threshold.
+func (config GateConfig) closedGain(level float64) float64 {
+	floor := math.Max(float64(config.Range), ratioToDB(0))
+	if config.Ratio == 0 {
+		return floor
+	}
+	return math.Max(floor, (level-float64(*config.Threshold))*(config.Ratio-1))
+}
+
+// Gate attenuates the segment while its level stays under the threshold, e.g. to mute
+// background noise between phrases. Levels are detected with a windowed RMS over all
+// channels, so that every channel gets the same gain.
+func (seg *AudioSegment) Gate(config *GateConfig) (*AudioSegment, error) {
+	c := config.withDefaults()
+	if c.Ratio != 0 && c.Ratio < 1 {
+		return nil, NewAudioSegmentError("ratio should be 0 or >= 1")
+	}
+
+	if c.Range > 0 {
+		return nil, NewAudioSegmentError("range should be negative")
+	}
+
+	if c.Attack < 0 || c.Hold < 0 || c.Release < 0 || c.Window < 0 {
+		return nil, NewAudioSegmentError("attack, hold, release and window should be positive")
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	levels := windowedLevels(samples, int(seg.FrameCountIn(c.Window)))
+	if len(levels) == 0 {
+		return seg, nil
+	}
+
+	holdFrames := int(c.Hold.Seconds() * float64(seg.frameRate))
+	held := 0
+
+	var initial float64
+	if first := ratioToDB(levels[0]); first < float64(*c.Threshold) {
+		initial = c.closedGain(first)
+	}
+
+	// The gain rises when the gate opens, so it follows the attack time.
+	envelope := newEnvelopeFollower(c.Attack, c.Release, seg.frameRate, initial)
+	for i, level := range levels {
+		level := ratioToDB(level)
+
+		var target float64
+		switch {
+		case level >= float64(*c.Threshold):
+			held = holdFrames
+		case held > 0:
+			held--
+		default:
+			target = c.closedGain(level)
+		}
+
+		ratio := Volume(envelope.next(target)).ToRatio(true)
+		for _, channel := range samples {
+			channel[i] *= ratio
+		}
+	}
+
+	return seg.deriveFromChannelSamples(samples)
+}
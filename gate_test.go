@@ -0,0 +1,74 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGate(t *testing.T) {
+	phrase := newSineSegment(t, 440, 0.5, 500*time.Millisecond, 1, 2)
+	hiss := newSineSegment(t, 3000, 0.001, time.Second, 1, 2)
+	seg, err := phrase.Append(hiss, phrase)
+	assert.Nil(t, err)
+	seg, err = seg.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+
+	gated, err := seg.Gate(&GateConfig{Threshold: VolumePtr(-40), Range: -60})
+	assert.Nil(t, err)
+	assert.Equal(t, seg.Duration(), gated.Duration())
+
+	slice := func(s *AudioSegment, start, end time.Duration) *AudioSegment {
+		r, err := s.Slice(start, end)
+		assert.Nil(t, err)
+		return r
+	}
+
+	// Phrases go through unchanged.
+	assert.InDelta(t, float64(phrase.DBFS()), float64(slice(gated, 0, 500*time.Millisecond).DBFS()), 0.1)
+	assert.InDelta(t, float64(phrase.DBFS()), float64(slice(gated, 1550*time.Millisecond, 2*time.Second).DBFS()), 0.1)
+
+	// The gate stays open during the hold time.
+	held := slice(gated, 520*time.Millisecond, 550*time.Millisecond)
+	assert.InDelta(t, float64(hiss.DBFS()), float64(held.DBFS()), 0.1)
+
+	// Then the hiss is attenuated by the range.
+	closed := slice(gated, time.Second, 1400*time.Millisecond)
+	assert.InDelta(t, float64(hiss.DBFS())-60, float64(closed.DBFS()), 0.5)
+}
+
+func TestGateExpander(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.03, time.Second, 2, 2)
+	assert.InDelta(t, -33.5, float64(seg.DBFS()), 0.1)
+
+	expanded, err := seg.Gate(&GateConfig{Threshold: VolumePtr(-20), Ratio: 2})
+	assert.Nil(t, err)
+	tail, _ := expanded.Slice(500*time.Millisecond, time.Second)
+	assert.InDelta(t, -47, float64(tail.DBFS()), 0.5)
+
+	loud := newSineSegment(t, 440, 0.5, time.Second, 2, 2)
+	expanded, err = loud.Gate(&GateConfig{Threshold: VolumePtr(-20), Ratio: 2})
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(loud.DBFS()), float64(expanded.DBFS()), 0.1)
+
+	_, err = seg.Gate(&GateConfig{Ratio: 0.5})
+	assert.Error(t, err)
+	_, err = seg.Gate(&GateConfig{Range: 6})
+	assert.Error(t, err)
+}
+
+func TestGateClosedGain(t *testing.T) {
+	gate := GateConfig{Threshold: VolumePtr(-40), Range: -80}
+	assert.Equal(t, -80.0, gate.closedGain(-50))
+
+	expander := GateConfig{Threshold: VolumePtr(-40), Range: -30, Ratio: 3}
+	assert.Equal(t, -20.0, expander.closedGain(-50))
+	assert.Equal(t, -30.0, expander.closedGain(-70))
+
+	muted := GateConfig{Threshold: VolumePtr(-40), Range: SilentVolume}
+	assert.Equal(t, -200.0, muted.closedGain(-50))
+
+	assert.Equal(t, Volume(-40), *GateConfig{}.withDefaults().Threshold)
+	assert.Equal(t, Volume(0), *GateConfig{Threshold: VolumePtr(0)}.withDefaults().Threshold)
+}
@@ -0,0 +1,101 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+// Limit keeps every sample at or below `ceiling` without the distortion of hard clipping.
+// The gain starts going down `lookahead` before a peak, so that it never has to change
+// abruptly, and recovers over `release` after it. Channels get the same gain, so that the
+// stereo image is kept. A few milliseconds of lookahead and 50 to 200ms of release suit
+// most material. To mix hot overlays safely, mix float audio (see ForkWithSampleFormat)
+// and limit it before converting it back to PCM.
+func (seg *AudioSegment) Limit(ceiling Volume, lookahead, release time.Duration) (*AudioSegment, error) {
+	if math.IsInf(float64(ceiling), 0) || math.IsNaN(float64(ceiling)) {
+		return nil, NewAudioSegmentError("ceiling should be finite")
+	}
+
+	if lookahead < 0 || release < 0 {
+		return nil, NewAudioSegmentError("lookahead and release should be positive")
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	gains := limiterGains(samples, ceiling.ToRatio(true), int(lookahead.Seconds()*float64(seg.frameRate)))
+
+	// The gain reduction falls back slowly, rising gain reduction is already smooth.
+	envelope := newEnvelopeFollower(0, release, seg.frameRate, 0)
+	for i, gain := range gains {
+		reduction := envelope.next(-ratioToDB(gain))
+		gain = math.Pow(10, -reduction/20)
+		for _, channel := range samples {
+			channel[i] *= gain
+		}
+	}
+
+	return seg.deriveFromChannelSamples(samples)
+}
+
+// limiterGains returns the gain of each frame, low enough to bring the peak of every frame
+// within the next `lookahead` frames under `ceiling`. Each gain is the average of the lowest
+// gains required over the `lookahead` frames before it, which ramps the gain down smoothly
+// and keeps it under the gain required by the current frame.
+func limiterGains(samples [][]float64, ceiling float64, lookahead int) []float64 {
+	frameCount := len(samples[0])
+	required := make([]float64, frameCount)
+	for i := range required {
+		var peak float64
+		for _, channel := range samples {
+			peak = math.Max(peak, math.Abs(channel[i]))
+		}
+
+		required[i] = 1
+		if peak > ceiling {
+			required[i] = ceiling / peak
+		}
+	}
+
+	lowest := slidingMinimum(required, lookahead+1)
+
+	sums := make([]float64, frameCount+1)
+	for i, v := range lowest {
+		sums[i+1] = sums[i] + v
+	}
+
+	gains := make([]float64, frameCount)
+	for i := range gains {
+		start := i - lookahead
+		if start < 0 {
+			start = 0
+		}
+
+		// Frames before the segment look ahead into it, lowest[0] is low enough for them.
+		missing := float64(lookahead - (i - start))
+		gains[i] = (sums[i+1] - sums[start] + missing*lowest[0]) / float64(lookahead+1)
+	}
+	return gains
+}
+
+// slidingMinimum returns the minimum of values[i:i+window] for each i.
+func slidingMinimum(values []float64, window int) []float64 {
+	result := make([]float64, len(values))
+
+	// Indexes of increasing values, the front is the minimum of the current window.
+	queue := make([]int, 0, window)
+	for i := len(values) - 1; i >= 0; i-- {
+		for len(queue) > 0 && values[queue[len(queue)-1]] >= values[i] {
+			queue = queue[:len(queue)-1]
+		}
+		queue = append(queue, i)
+
+		if queue[0] >= i+window {
+			queue = queue[1:]
+		}
+		result[i] = values[queue[0]]
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package godub
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.25, time.Second, 2, 2)
+	float, err := seg.ForkWithSampleFormat(SampleFormatFloat, 4)
+	assert.Nil(t, err)
+
+	// A hot mix goes over full scale.
+	hot, err := float.Overlay(float, &OverlayConfig{})
+	assert.Nil(t, err)
+	hot, err = hot.Overlay(float, &OverlayConfig{})
+	assert.Nil(t, err)
+	hot, err = hot.Overlay(float, &OverlayConfig{})
+	assert.Nil(t, err)
+	assert.InDelta(t, 1, hot.Max(), 0.01)
+	hot, err = hot.ApplyGain(6)
+	assert.Nil(t, err)
+
+	limited, err := hot.Limit(-1, 5*time.Millisecond, 100*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, hot.Duration(), limited.Duration())
+	assert.True(t, limited.MaxDBFS() <= -1)
+	assert.InDelta(t, -1, float64(limited.MaxDBFS()), 0.1)
+
+	// It's still the same sine, not a clipped one.
+	assert.InDelta(t, 440, estimateFrequency(t, limited), 2)
+	assert.InDelta(t, float64(limited.MaxDBFS()-3.01), float64(limited.DBFS()), 0.2)
+
+	pcm, err := limited.ForkWithSampleFormat(SampleFormatPCM, 2)
+	assert.Nil(t, err)
+	assert.True(t, pcm.MaxDBFS() <= -0.99)
+}
+
+func TestLimitTransient(t *testing.T) {
+	// A quiet sine with a single loud click in the middle.
+	seg := newSineSegment(t, 440, 0.1, time.Second, 1, 2)
+	samples, err := seg.GetChannelFloatSamples()
+	assert.Nil(t, err)
+	samples[0][4000] = 0.9
+	seg, err = NewAudioSegmentFromChannelFloatSamples(samples, seg.FrameRate())
+	assert.Nil(t, err)
+
+	limited, err := seg.Limit(-12, 5*time.Millisecond, 50*time.Millisecond)
+	assert.Nil(t, err)
+	assert.True(t, limited.MaxDBFS() <= -12)
+
+	// Audio away from the click is untouched.
+	limitedSamples, err := limited.GetChannelFloatSamples()
+	assert.Nil(t, err)
+	for _, i := range []int{1000, 3000, 6000} {
+		assert.InDelta(t, samples[0][i], limitedSamples[0][i], 1e-6)
+	}
+
+	// The gain goes down smoothly before the click.
+	ratio := func(i int) float64 {
+		return limitedSamples[0][i] / samples[0][i]
+	}
+	assert.InDelta(t, 1, ratio(3955), 0.01)
+	assert.True(t, ratio(3990) < ratio(3970))
+	assert.True(t, ratio(3970) < ratio(3960))
+
+	// Nothing to do under the ceiling.
+	quiet, err := seg.Limit(0, 5*time.Millisecond, 50*time.Millisecond)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(seg.MaxDBFS()), float64(quiet.MaxDBFS()), 1e-6)
+
+	_, err = seg.Limit(Volume(math.Inf(1)), 0, 0)
+	assert.Error(t, err)
+	_, err = seg.Limit(0, -time.Millisecond, 0)
+	assert.Error(t, err)
+}
+
+func TestSlidingMinimum(t *testing.T) {
+	values := []float64{5, 3, 4, 1, 6, 2, 7}
+	assert.Equal(t, []float64{3, 1, 1, 1, 2, 2, 7}, slidingMinimum(values, 3))
+	assert.Equal(t, values, slidingMinimum(values, 1))
+}
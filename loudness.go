@@ -0,0 +1,295 @@
+package godub
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/iFaceless/godub/filter"
+)
+
+const (
+	// momentaryWindow and shortTermWindow are the measurement windows of EBU R128.
+	momentaryWindow = 400 * time.Millisecond
+	shortTermWindow = 3 * time.Second
+	// loudnessStep is the distance between successive measurement windows.
+	loudnessStep = 100 * time.Millisecond
+
+	// absoluteGate drops silent blocks from the integrated loudness and the loudness range.
+	absoluteGate = -70
+	// integratedRelativeGate and rangeRelativeGate are relative to the ungated loudness, in LU.
+	integratedRelativeGate = -10
+	rangeRelativeGate      = -20
+)
+
+// Loudness holds the loudness of a segment as defined by ITU-R BS.1770 and EBU R128.
+// Loudness values are in LUFS, the range is in LU; silence is -Inf LUFS.
+type Loudness struct {
+	// Integrated is the gated loudness of the whole segment.
+	Integrated float64
+	// Range (LRA) is the spread between soft and loud parts, from the short-term loudness.
+	Range float64
+	// MaxMomentary is the highest loudness over 400ms.
+	MaxMomentary float64
+	// MaxShortTerm is the highest loudness over 3s.
+	MaxShortTerm float64
+}
+
+// Loudness measures the loudness of the segment.
+func (seg *AudioSegment) Loudness() (*Loudness, error) {
+	power, err := seg.weightedPower()
+	if err != nil {
+		return nil, err
+	}
+
+	momentary := seg.blockLoudness(power, momentaryWindow)
+	shortTerm := seg.blockLoudness(power, shortTermWindow)
+	return &Loudness{
+		Integrated:   integratedLoudness(momentary),
+		Range:        loudnessRange(shortTerm),
+		MaxMomentary: maxLoudness(momentary),
+		MaxShortTerm: maxLoudness(shortTerm),
+	}, nil
+}
+
+// IntegratedLoudness returns the gated loudness of the whole segment, in LUFS.
+func (seg *AudioSegment) IntegratedLoudness() (float64, error) {
+	power, err := seg.weightedPower()
+	if err != nil {
+		return 0, err
+	}
+	return integratedLoudness(seg.blockLoudness(power, momentaryWindow)), nil
+}
+
+// MomentaryLoudness returns the loudness over 400ms windows every 100ms, in LUFS.
+func (seg *AudioSegment) MomentaryLoudness() ([]float64, error) {
+	power, err := seg.weightedPower()
+	if err != nil {
+		return nil, err
+	}
+	return seg.blockLoudness(power, momentaryWindow), nil
+}
+
+// ShortTermLoudness returns the loudness over 3s windows every 100ms, in LUFS.
+func (seg *AudioSegment) ShortTermLoudness() ([]float64, error) {
+	power, err := seg.weightedPower()
+	if err != nil {
+		return nil, err
+	}
+	return seg.blockLoudness(power, shortTermWindow), nil
+}
+
+// LoudnessRange returns the loudness range (LRA) of the segment, in LU.
+func (seg *AudioSegment) LoudnessRange() (float64, error) {
+	power, err := seg.weightedPower()
+	if err != nil {
+		return 0, err
+	}
+	return loudnessRange(seg.blockLoudness(power, shortTermWindow)), nil
+}
+
+// NormalizeLoudness applies gain so that the integrated loudness reaches `targetLUFS`, e.g. -14
+// or -16 for streaming platforms. The gain is lowered if needed to keep the true peak at or below
+// `truePeakCeiling` dBTP, so the target may not be reached for very dynamic audio. It returns the
+// normalized segment and the gain applied, a silent segment is returned unchanged with zero gain.
+// Audio quieter than the -70 LUFS gate has no integrated loudness and returns an error.
+func (seg *AudioSegment) NormalizeLoudness(targetLUFS float64, truePeakCeiling Volume) (*AudioSegment, Volume, error) {
+	integrated, err := seg.IntegratedLoudness()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if math.IsInf(integrated, -1) {
+		if seg.Max() > 0 {
+			return nil, 0, NewAudioSegmentError("audio is too quiet to measure its loudness")
+		}
+		return seg, 0, nil
+	}
+
+	peak, err := seg.maxTruePeak()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	gain := Volume(targetLUFS - integrated)
+	if peak+gain > truePeakCeiling {
+		gain = truePeakCeiling - peak
+	}
+
+	normalized, err := seg.ApplyGain(gain)
+	if err != nil {
+		return nil, 0, err
+	}
+	return normalized, gain, nil
+}
+
+// weightedPower returns the running sum of the K-weighted power of all channels,
+// see prefixSumOfSquares. Channels are weighted as described in BS.1770.
+func (seg *AudioSegment) weightedPower() ([]float64, error) {
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := kWeighting(float64(seg.frameRate))
+	if err != nil {
+		return nil, err
+	}
+
+	weights := channelWeights(len(samples))
+	weighted := make([][]float64, 0, len(samples))
+	for c, channel := range samples {
+		if weights[c] == 0 {
+			continue
+		}
+
+		channel = k.Process(channel)
+		scale := math.Sqrt(weights[c])
+		for i := range channel {
+			channel[i] *= scale
+		}
+		weighted = append(weighted, channel)
+	}
+
+	return prefixSumOfSquares(weighted), nil
+}
+
+// kWeighting returns the K-weighting filter of BS.1770: a high shelf modelling the head,
+// followed by a high-pass filter. The coefficients given for 48kHz in the standard are
+// derived for any frame rate, like libebur128 does.
+func kWeighting(frameRate float64) (filter.Cascade, error) {
+	if frameRate <= 2*1681.974450955533 {
+		return nil, NewAudioSegmentError("frame rate is too low to measure loudness")
+	}
+
+	k := math.Tan(math.Pi * 1681.974450955533 / frameRate)
+	q := 0.7071752369554196
+	vh := math.Pow(10, 3.999843853973347/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := &filter.Biquad{
+		B0: (vh + vb*k/q + k*k) / a0,
+		B1: 2 * (k*k - vh) / a0,
+		B2: (vh - vb*k/q + k*k) / a0,
+		A1: 2 * (k*k - 1) / a0,
+		A2: (1 - k/q + k*k) / a0,
+	}
+
+	k = math.Tan(math.Pi * 38.13547087613982 / frameRate)
+	q = 0.5003270373253953
+	a0 = 1 + k/q + k*k
+	highPass := &filter.Biquad{
+		B0: 1,
+		B1: -2,
+		B2: 1,
+		A1: 2 * (k*k - 1) / a0,
+		A2: (1 - k/q + k*k) / a0,
+	}
+	return filter.Cascade{shelf, highPass}, nil
+}
+
+// channelWeights returns the BS.1770 weight of each channel: surround channels of 5.1 and
+// 7.1 audio are boosted by 1.5dB and the LFE channel is ignored.
+func channelWeights(channels int) []float64 {
+	weights := make([]float64, channels)
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	if channels == 6 || channels == 8 {
+		weights[3] = 0
+		for i := 4; i < channels; i++ {
+			weights[i] = 1.41
+		}
+	}
+	return weights
+}
+
+// blockLoudness returns the loudness of windows of length `window` every 100ms. Segments
+// shorter than the window are measured as a single block.
+func (seg *AudioSegment) blockLoudness(power []float64, window time.Duration) []float64 {
+	frameCount := len(power) - 1
+	if frameCount <= 0 {
+		return nil
+	}
+
+	length := int(window.Seconds() * float64(seg.frameRate))
+	step := int(loudnessStep.Seconds() * float64(seg.frameRate))
+	if step < 1 {
+		step = 1
+	}
+
+	if length > frameCount {
+		length = frameCount
+	}
+
+	result := make([]float64, 0, (frameCount-length)/step+1)
+	for start := 0; start+length <= frameCount; start += step {
+		meanSquare := (power[start+length] - power[start]) / float64(length)
+		result = append(result, powerToLUFS(meanSquare))
+	}
+	return result
+}
+
+// integratedLoudness gates the momentary blocks, first with the absolute gate then
+// 10 LU below the loudness of the remaining blocks.
+func integratedLoudness(momentary []float64) float64 {
+	blocks := gateBlocks(momentary, absoluteGate)
+	relativeGate := meanLoudness(blocks) + integratedRelativeGate
+	return meanLoudness(gateBlocks(blocks, relativeGate))
+}
+
+// loudnessRange is the spread between the 10th and 95th percentiles of the gated short-term blocks.
+func loudnessRange(shortTerm []float64) float64 {
+	blocks := gateBlocks(shortTerm, absoluteGate)
+	relativeGate := meanLoudness(blocks) + rangeRelativeGate
+	blocks = gateBlocks(blocks, relativeGate)
+	if len(blocks) == 0 {
+		return 0
+	}
+
+	sort.Float64s(blocks)
+	percentile := func(p float64) float64 {
+		return blocks[int(math.Round(p*float64(len(blocks)-1)))]
+	}
+	return percentile(0.95) - percentile(0.10)
+}
+
+// gateBlocks keeps the blocks louder than `gate`.
+func gateBlocks(blocks []float64, gate float64) []float64 {
+	result := make([]float64, 0, len(blocks))
+	for _, l := range blocks {
+		if l > gate {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+// meanLoudness averages the power of the blocks, not their loudness.
+func meanLoudness(blocks []float64) float64 {
+	if len(blocks) == 0 {
+		return math.Inf(-1)
+	}
+
+	var sum float64
+	for _, l := range blocks {
+		sum += math.Pow(10, (l+0.691)/10)
+	}
+	return powerToLUFS(sum / float64(len(blocks)))
+}
+
+func maxLoudness(blocks []float64) float64 {
+	max := math.Inf(-1)
+	for _, l := range blocks {
+		max = math.Max(max, l)
+	}
+	return max
+}
+
+func powerToLUFS(meanSquare float64) float64 {
+	if meanSquare <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(meanSquare)
+}
@@ -0,0 +1,120 @@
+package godub
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTone creates a 997Hz stereo sine wave at 48kHz, `level` is the peak level in dBFS.
+func newTone(t *testing.T, level Volume, duration time.Duration) *AudioSegment {
+	frameRate := 48000
+	amplitude := level.ToRatio(true)
+	samples := make([]float64, int(duration.Seconds()*float64(frameRate)))
+	for i := range samples {
+		samples[i] = amplitude * math.Sin(2*math.Pi*997*float64(i)/float64(frameRate))
+	}
+
+	seg, err := NewAudioSegmentFromChannelFloatSamples([][]float64{samples, samples}, uint32(frameRate))
+	assert.Nil(t, err)
+	return seg
+}
+
+func TestIntegratedLoudness(t *testing.T) {
+	// EBU Tech 3341, test case 1 and 2.
+	for _, level := range []Volume{-23, -33} {
+		loudness, err := newTone(t, level, 20*time.Second).IntegratedLoudness()
+		assert.Nil(t, err)
+		assert.InDelta(t, float64(level), loudness, 0.1)
+	}
+
+	// EBU Tech 3341, test case 3: the quiet parts are removed by the relative gate.
+	seg, err := newTone(t, -36, 10*time.Second).Append(newTone(t, -23, 60*time.Second), newTone(t, -36, 10*time.Second))
+	assert.Nil(t, err)
+	loudness, err := seg.IntegratedLoudness()
+	assert.Nil(t, err)
+	assert.InDelta(t, -23, loudness, 0.1)
+
+	// Silence is removed by the absolute gate.
+	silence, err := NewSilentAudioSegment(10000, 48000)
+	assert.Nil(t, err)
+	seg, err = newTone(t, -23, 20*time.Second).Append(silence)
+	assert.Nil(t, err)
+	loudness, err = seg.IntegratedLoudness()
+	assert.Nil(t, err)
+	assert.InDelta(t, -23, loudness, 0.1)
+
+	loudness, err = silence.IntegratedLoudness()
+	assert.Nil(t, err)
+	assert.True(t, math.IsInf(loudness, -1))
+}
+
+func TestLoudnessRange(t *testing.T) {
+	// EBU Tech 3342, test case 1 and 2.
+	for _, levels := range [][2]Volume{{-20, -30}, {-20, -15}} {
+		seg, err := newTone(t, levels[0], 20*time.Second).Append(newTone(t, levels[1], 20*time.Second))
+		assert.Nil(t, err)
+
+		loudness, err := seg.Loudness()
+		assert.Nil(t, err)
+		assert.InDelta(t, math.Abs(float64(levels[0]-levels[1])), loudness.Range, 1)
+		assert.InDelta(t, math.Max(float64(levels[0]), float64(levels[1])), loudness.MaxMomentary, 0.1)
+		assert.InDelta(t, math.Max(float64(levels[0]), float64(levels[1])), loudness.MaxShortTerm, 0.1)
+	}
+
+	lra, err := newTone(t, -23, 10*time.Second).LoudnessRange()
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, lra, 0.1)
+}
+
+func TestMomentaryAndShortTermLoudness(t *testing.T) {
+	seg := newTone(t, -23, 5*time.Second)
+
+	momentary, err := seg.MomentaryLoudness()
+	assert.Nil(t, err)
+	assert.Len(t, momentary, 47)
+
+	shortTerm, err := seg.ShortTermLoudness()
+	assert.Nil(t, err)
+	assert.Len(t, shortTerm, 21)
+	for _, l := range shortTerm {
+		assert.InDelta(t, -23, l, 0.1)
+	}
+
+	// A segment shorter than the window is measured as a whole.
+	short, err := seg.Slice(0, 200*time.Millisecond)
+	assert.Nil(t, err)
+	momentary, err = short.MomentaryLoudness()
+	assert.Nil(t, err)
+	assert.Len(t, momentary, 1)
+	assert.InDelta(t, -23, momentary[0], 0.1)
+}
+
+func TestNormalizeLoudness(t *testing.T) {
+	seg := newTone(t, -30, 10*time.Second)
+
+	normalized, gain, err := seg.NormalizeLoudness(-16, -1)
+	assert.Nil(t, err)
+	assert.InDelta(t, 14, float64(gain), 0.1)
+	loudness, err := normalized.IntegratedLoudness()
+	assert.Nil(t, err)
+	assert.InDelta(t, -16, loudness, 0.1)
+
+	// The peak stays below the ceiling, even if the target isn't reached.
+	normalized, gain, err = seg.NormalizeLoudness(0, -1)
+	assert.Nil(t, err)
+	assert.InDelta(t, 29, float64(gain), 0.01)
+	assert.InDelta(t, -1, float64(normalized.MaxDBFS()), 0.01)
+
+	silence, err := NewSilentAudioSegment(1000, 48000)
+	assert.Nil(t, err)
+	normalized, gain, err = silence.NormalizeLoudness(-16, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, Volume(0), gain)
+	assert.True(t, silence.Equal(normalized))
+
+	_, _, err = newTone(t, -80, time.Second).NormalizeLoudness(-16, -1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package godub
+
+var (
+	// stereoToMidSide encodes left and right as mid (L+R)/2 and side (L-R)/2.
+	stereoToMidSide = [][]float64{
+		{0.5, 0.5},
+		{0.5, -0.5},
+	}
+	// midSideToStereo decodes mid and side as left M+S and right M-S.
+	midSideToStereo = [][]float64{
+		{1, 1},
+		{1, -1},
+	}
+	// centerCancellation keeps the side signal (L-R)/2 in both channels.
+	centerCancellation = [][]float64{
+		{0.5, -0.5},
+		{0.5, -0.5},
+	}
+)
+
+// InvertPhase flips the polarity of every sample.
+func (seg *AudioSegment) InvertPhase() (*AudioSegment, error) {
+	return seg.mul(-1)
+}
+
+// ToMidSide encodes a stereo segment as mid/side: the first channel holds
+// the mid signal (L+R)/2 and the second one holds the side signal (L-R)/2.
+func (seg *AudioSegment) ToMidSide() (*AudioSegment, error) {
+	if seg.channels != 2 {
+		return nil, NewAudioSegmentError("mid/side encoding requires stereo audio")
+	}
+	return seg.ForkWithChannelMatrix(stereoToMidSide)
+}
+
+// FromMidSide decodes a mid/side segment created by ToMidSide back to left and right.
+func (seg *AudioSegment) FromMidSide() (*AudioSegment, error) {
+	if seg.channels != 2 {
+		return nil, NewAudioSegmentError("mid/side decoding requires stereo audio")
+	}
+	return seg.ForkWithChannelMatrix(midSideToStereo)
+}
+
+// RemoveCenter cancels everything panned to the center, which usually removes
+// the lead vocals of a mix. Both channels of the result hold the side signal.
+func (seg *AudioSegment) RemoveCenter() (*AudioSegment, error) {
+	if seg.channels != 2 {
+		return nil, NewAudioSegmentError("center cancellation requires stereo audio")
+	}
+	return seg.ForkWithChannelMatrix(centerCancellation)
+}
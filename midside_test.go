@@ -0,0 +1,58 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMidSide(t *testing.T) {
+	left := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+	right := newSineSegment(t, 220, 0.3, 100*time.Millisecond, 1, 2)
+	stereo, _ := NewAudioSegmentFromMonoSegments(left, right)
+
+	midSide, err := stereo.ToMidSide()
+	assert.Nil(t, err)
+
+	decoded, err := midSide.FromMidSide()
+	assert.Nil(t, err)
+	original, _ := stereo.channelSamples()
+	samples, _ := decoded.channelSamples()
+	for c := range samples {
+		assert.InDeltaSlice(t, original[c], samples[c], 1e-4)
+	}
+
+	_, err = left.ToMidSide()
+	assert.Error(t, err)
+}
+
+func TestRemoveCenter(t *testing.T) {
+	center := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 2)
+	removed, err := center.RemoveCenter()
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, removed.Max())
+}
+
+func TestInvertPhase(t *testing.T) {
+	for _, width := range []uint16{1, 2, 4} {
+		seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, width)
+		inverted, err := seg.InvertPhase()
+		assert.Nil(t, err)
+
+		original, _ := seg.channelSamples()
+		samples, _ := inverted.channelSamples()
+		for i := range samples[0] {
+			assert.InDelta(t, -original[0][i], samples[0][i], 1.0/seg.MaxPossibleAmplitude())
+		}
+	}
+}
+
+func TestInvertPhaseClipsLowest32BitSample(t *testing.T) {
+	seg, err := NewAudioSegment([]byte{0x00, 0x00, 0x00, 0x80}, SampleWidth(4), FrameRate(8000), Channels(1))
+	assert.Nil(t, err)
+
+	inverted, err := seg.InvertPhase()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xff, 0xff, 0xff, 0x7f}, inverted.RawData())
+}
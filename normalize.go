@@ -0,0 +1,33 @@
+package godub
+
+// Normalize applies gain so that the peak sample sits `headroom` below 0dBFS.
+// It returns the normalized segment and the gain applied, a silent segment is
+// returned unchanged with zero gain.
+func (seg *AudioSegment) Normalize(headroom Volume) (*AudioSegment, Volume, error) {
+	if seg.Max() == 0 {
+		return seg, 0, nil
+	}
+
+	gain := -headroom - seg.MaxDBFS()
+	normalized, err := seg.ApplyGain(gain)
+	if err != nil {
+		return nil, 0, err
+	}
+	return normalized, gain, nil
+}
+
+// NormalizeRMS applies gain so that the average loudness (RMS) reaches `target`.
+// It returns the normalized segment and the gain applied, a silent segment is
+// returned unchanged with zero gain. Peaks pushed over 0dBFS are clipped.
+func (seg *AudioSegment) NormalizeRMS(target Volume) (*AudioSegment, Volume, error) {
+	if seg.RMS() == 0 {
+		return seg, 0, nil
+	}
+
+	gain := target - seg.DBFS()
+	normalized, err := seg.ApplyGain(gain)
+	if err != nil {
+		return nil, 0, err
+	}
+	return normalized, gain, nil
+}
@@ -0,0 +1,49 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.25, time.Second, 2, 2)
+
+	normalized, gain, err := seg.Normalize(1)
+	assert.Nil(t, err)
+	assert.InDelta(t, 11.04, float64(gain), 0.01)
+	assert.InDelta(t, -1, float64(normalized.MaxDBFS()), 0.01)
+
+	normalized, gain, err = seg.NormalizeRMS(-20)
+	assert.Nil(t, err)
+	assert.InDelta(t, -20, float64(normalized.DBFS()), 0.01)
+
+	silence, _ := NewSilentAudioSegment(100, 8000)
+	normalized, gain, err = silence.Normalize(0)
+	assert.Nil(t, err)
+	assert.Equal(t, Volume(0), gain)
+	assert.True(t, normalized.Equal(silence))
+
+	_, gain, err = silence.NormalizeRMS(-20)
+	assert.Nil(t, err)
+	assert.Equal(t, Volume(0), gain)
+}
+
+func TestNormalizeRMSClips32BitAudio(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.9, 100*time.Millisecond, 1, 4)
+	normalized, _, err := seg.NormalizeRMS(0)
+	assert.Nil(t, err)
+
+	original, _ := seg.channelSamples()
+	samples, _ := normalized.channelSamples()
+	for i := range samples[0] {
+		assert.False(t, original[0][i]*samples[0][i] < 0, "sample %d changed sign", i)
+	}
+}
+
+func TestDBFSOf8BitAudio(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 1, 1)
+	assert.InDelta(t, -6, float64(seg.MaxDBFS()), 0.1)
+	assert.InDelta(t, -9, float64(seg.DBFS()), 0.2)
+}
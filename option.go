@@ -25,3 +25,9 @@ func Channels(v uint16) AudioSegmentOption {
 		s.channels = v
 	}
 }
+
+func Format(v SampleFormat) AudioSegmentOption {
+	return func(s *AudioSegment) {
+		s.sampleFormat = v
+	}
+}
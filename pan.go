@@ -0,0 +1,71 @@
+package godub
+
+import "math"
+
+// Pan places the audio in the stereo field with a constant-power pan law,
+// `position` goes from -1 (hard left) through 0 (center) to 1 (hard right).
+// Mono audio is upmixed to stereo first. For stereo audio the overall power
+// is kept: the center leaves the audio unchanged while the sides boost one
+// channel by 3dB and mute the other.
+func (seg *AudioSegment) Pan(position float64) (*AudioSegment, error) {
+	if position < -1 || position > 1 {
+		return nil, NewAudioSegmentError("pan position should be between -1 and 1")
+	}
+
+	samples, err := seg.stereoSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	angle := (position + 1) * math.Pi / 4
+	leftGain, rightGain := math.Cos(angle), math.Sin(angle)
+	if seg.channels == 2 {
+		leftGain *= math.Sqrt2
+		rightGain *= math.Sqrt2
+	}
+
+	return seg.deriveFromStereoSamples(samples, leftGain, rightGain)
+}
+
+// Balance attenuates one side of the stereo field, `balance` goes from -1 (only left)
+// through 0 (unchanged) to 1 (only right). Mono audio is upmixed to stereo first.
+func (seg *AudioSegment) Balance(balance float64) (*AudioSegment, error) {
+	if balance < -1 || balance > 1 {
+		return nil, NewAudioSegmentError("balance should be between -1 and 1")
+	}
+
+	samples, err := seg.stereoSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	leftGain, rightGain := math.Min(1, 1-balance), math.Min(1, 1+balance)
+	return seg.deriveFromStereoSamples(samples, leftGain, rightGain)
+}
+
+// stereoSamples returns the left and right samples, mono audio is duplicated.
+func (seg *AudioSegment) stereoSamples() ([][]float64, error) {
+	if seg.channels != 1 && seg.channels != 2 {
+		return nil, NewAudioSegmentError("expected mono or stereo audio, got %d channels", seg.channels)
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	if seg.channels == 1 {
+		right := make([]float64, len(samples[0]))
+		copy(right, samples[0])
+		samples = append(samples, right)
+	}
+	return samples, nil
+}
+
+func (seg *AudioSegment) deriveFromStereoSamples(samples [][]float64, leftGain, rightGain float64) (*AudioSegment, error) {
+	for i := range samples[0] {
+		samples[0][i] *= leftGain
+		samples[1][i] *= rightGain
+	}
+	return seg.deriveFromChannelSamples(samples)
+}
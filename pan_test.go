@@ -0,0 +1,43 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPan(t *testing.T) {
+	mono := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+
+	panned, err := mono.Pan(-1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(2), panned.Channels())
+
+	samples, _ := panned.channelSamples()
+	left := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+	leftSamples, _ := left.channelSamples()
+	assert.InDeltaSlice(t, leftSamples[0], samples[0], 1e-4)
+	assert.Equal(t, make([]float64, len(samples[1])), samples[1])
+
+	panned, err = mono.Pan(0)
+	assert.Nil(t, err)
+	assert.InDelta(t, float64(mono.DBFS())-3, float64(panned.DBFS()), 0.05)
+
+	_, err = mono.Pan(2)
+	assert.Error(t, err)
+}
+
+func TestBalance(t *testing.T) {
+	stereo := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 2)
+
+	balanced, err := stereo.Balance(0.5)
+	assert.Nil(t, err)
+
+	samples, _ := balanced.channelSamples()
+	original, _ := stereo.channelSamples()
+	assert.InDeltaSlice(t, original[1], samples[1], 1e-9)
+	for i := range samples[0] {
+		assert.InDelta(t, original[0][i]/2, samples[0][i], 1e-4)
+	}
+}
@@ -0,0 +1,63 @@
+package godub
+
+import "math"
+
+// standardFrameRates are the frame rates that encoders commonly support.
+var standardFrameRates = []uint32{
+	8000, 11025, 16000, 22050, 24000, 32000, 44100, 48000, 88200, 96000, 176400, 192000,
+}
+
+// PitchShift shifts the pitch by `semitones` (fractions allowed, negative goes down)
+// and keeps the duration. The segment is time-stretched first, then played back
+// faster or slower to restore the original length, which scales all frequencies.
+func (seg *AudioSegment) PitchShift(semitones float64) (*AudioSegment, error) {
+	if semitones == 0 {
+		return seg, nil
+	}
+
+	ratio := math.Pow(2, semitones/12)
+	stretched, err := seg.TimeStretch(ratio)
+	if err != nil {
+		return nil, err
+	}
+	return stretched.ChangePlaybackRate(ratio, true)
+}
+
+// ChangePlaybackRate plays the segment `rate` times faster like a tape machine, so
+// that both the duration and the pitch change, e.g. 2 halves the duration and raises
+// the pitch by an octave. The audio is resampled back to the original frame rate if
+// `keepFrameRate` is true, otherwise to the standard frame rate closest to the
+// original one multiplied by `rate`.
+func (seg *AudioSegment) ChangePlaybackRate(rate float64, keepFrameRate bool) (*AudioSegment, error) {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, NewAudioSegmentError("playback rate should be > 0")
+	}
+
+	playbackFrameRate := math.Round(float64(seg.frameRate) * rate)
+	if playbackFrameRate < 1 || playbackFrameRate > math.MaxUint32 {
+		return nil, NewAudioSegmentError("playback rate %.3f is out of range", rate)
+	}
+
+	frameRate := seg.frameRate
+	if !keepFrameRate {
+		frameRate = nearestStandardFrameRate(playbackFrameRate)
+	}
+
+	// Reinterpreting the samples at another frame rate changes the speed,
+	// resampling then brings it to the frame rate we want.
+	played, err := seg.derive(seg.data, FrameRate(uint32(playbackFrameRate)))
+	if err != nil {
+		return nil, err
+	}
+	return played.ForkWithFrameRate(int(frameRate))
+}
+
+func nearestStandardFrameRate(frameRate float64) uint32 {
+	nearest := standardFrameRates[0]
+	for _, r := range standardFrameRates {
+		if math.Abs(float64(r)-frameRate) < math.Abs(float64(nearest)-frameRate) {
+			nearest = r
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,45 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPitchShift(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 2, 2)
+
+	shifted, err := seg.PitchShift(12)
+	assert.Nil(t, err)
+	assert.Equal(t, seg.Duration(), shifted.Duration())
+	assert.Equal(t, uint16(2), shifted.Channels())
+	assert.InDelta(t, 880, estimateFrequency(t, shifted), 10)
+
+	shifted, err = seg.PitchShift(-2.5)
+	assert.Nil(t, err)
+	assert.Equal(t, seg.Duration(), shifted.Duration())
+	assert.InDelta(t, 380.9, estimateFrequency(t, shifted), 5)
+}
+
+func TestChangePlaybackRate(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 1, 2)
+
+	faster, err := seg.ChangePlaybackRate(2, true)
+	assert.Nil(t, err)
+	assert.Equal(t, seg.FrameRate(), faster.FrameRate())
+	assert.Equal(t, 500*time.Millisecond, faster.Duration())
+	assert.InDelta(t, 880, estimateFrequency(t, faster), 10)
+
+	slower, err := seg.ChangePlaybackRate(1.1, false)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(8000), slower.FrameRate())
+	assert.InDelta(t, 909, float64(slower.Duration()/time.Millisecond), 1)
+
+	slower, err = seg.ChangePlaybackRate(2.8, false)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(22050), slower.FrameRate())
+
+	_, err = seg.ChangePlaybackRate(-1, true)
+	assert.Error(t, err)
+}
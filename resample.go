@@ -0,0 +1,117 @@
+package godub
+
+import "math"
+
+// ResampleQuality selects the algorithm used to change the frame rate.
+type ResampleQuality int
+
+const (
+	// ResampleDefault is the quality used when none is given, currently ResampleMedium.
+	ResampleDefault ResampleQuality = iota
+	// ResampleLinear interpolates linearly with audioop.Ratecv, like pydub does.
+	// It's the fastest, but aliases audibly when downsampling.
+	ResampleLinear
+	// ResampleLow uses a short windowed-sinc filter.
+	ResampleLow
+	// ResampleMedium uses a windowed-sinc filter good enough for most uses.
+	ResampleMedium
+	// ResampleHigh uses a long windowed-sinc filter with a steep rolloff.
+	ResampleHigh
+)
+
+// sincKernels holds the interpolation kernel of each windowed-sinc quality.
+var sincKernels = map[ResampleQuality]*sincKernel{
+	ResampleLow:    newSincKernel(8, 6, 0.9),
+	ResampleMedium: newSincKernel(16, 8.6, 0.94),
+	ResampleHigh:   newSincKernel(32, 10, 0.97),
+}
+
+// sincKernel is a Kaiser-windowed sinc, tabulated for speed.
+type sincKernel struct {
+	// zeroCrossings is the number of zero crossings on each side of the center.
+	zeroCrossings int
+	// rolloff is the cutoff relative to the Nyquist frequency, leaving room for the transition band.
+	rolloff float64
+	// resolution is the number of table entries per zero crossing.
+	resolution int
+	table      []float64
+}
+
+func newSincKernel(zeroCrossings int, beta, rolloff float64) *sincKernel {
+	k := &sincKernel{zeroCrossings: zeroCrossings, rolloff: rolloff, resolution: 512}
+
+	size := zeroCrossings*k.resolution + 1
+	k.table = make([]float64, size+1)
+	for i := 0; i < size; i++ {
+		x := float64(i) / float64(k.resolution)
+		r := x / float64(zeroCrossings)
+		window := besselI0(beta*math.Sqrt(1-r*r)) / besselI0(beta)
+		k.table[i] = sinc(x) * window
+	}
+	return k
+}
+
+// at returns the kernel value at distance x, in zero crossings.
+func (k *sincKernel) at(x float64) float64 {
+	x = math.Abs(x) * float64(k.resolution)
+	i := int(x)
+	if i >= len(k.table)-2 {
+		return 0
+	}
+	frac := x - float64(i)
+	return k.table[i] + frac*(k.table[i+1]-k.table[i])
+}
+
+// resample converts samples from `inRate` to `outRate` with a band-limited interpolation.
+func resample(samples []float64, inRate, outRate int, quality ResampleQuality) []float64 {
+	k, ok := sincKernels[quality]
+	if !ok {
+		k = sincKernels[ResampleMedium]
+	}
+	step := float64(inRate) / float64(outRate)
+
+	// Lower the cutoff when downsampling to remove frequencies above the new Nyquist.
+	cutoff := k.rolloff * math.Min(1, 1/step)
+	halfWidth := float64(k.zeroCrossings) / cutoff
+
+	outputLength := int(math.Round(float64(len(samples)) / step))
+	result := make([]float64, outputLength)
+	for j := range result {
+		t := float64(j) * step
+		from := int(math.Ceil(t - halfWidth))
+		to := int(math.Floor(t + halfWidth))
+		if from < 0 {
+			from = 0
+		}
+		if to > len(samples)-1 {
+			to = len(samples) - 1
+		}
+
+		var sum float64
+		for i := from; i <= to; i++ {
+			sum += samples[i] * k.at(cutoff*(t-float64(i)))
+		}
+		result[j] = sum * cutoff
+	}
+	return result
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// besselI0 is the zeroth order modified Bessel function of the first kind.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	for k := 1; k < 50; k++ {
+		term *= (x / (2 * float64(k))) * (x / (2 * float64(k)))
+		sum += term
+		if term < sum*1e-12 {
+			break
+		}
+	}
+	return sum
+}
@@ -0,0 +1,62 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkWithFrameRate(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 2, 2)
+
+	for _, quality := range []ResampleQuality{ResampleLinear, ResampleLow, ResampleMedium, ResampleHigh} {
+		upsampled, err := seg.ForkWithFrameRateQuality(22050, quality)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(22050), upsampled.FrameRate())
+		assert.Equal(t, seg.Duration(), upsampled.Duration())
+		assert.InDelta(t, 440, estimateFrequency(t, upsampled), 2)
+		assert.InDelta(t, float64(seg.DBFS()), float64(upsampled.DBFS()), 0.2)
+	}
+
+	_, err := seg.ForkWithFrameRate(0)
+	assert.Error(t, err)
+}
+
+func TestResampleRemovesAliases(t *testing.T) {
+	// 3kHz can't be represented at 4kHz, a band-limited resampler removes it.
+	seg := newSineSegment(t, 3000, 0.5, time.Second, 1, 2)
+
+	downsampled, err := seg.ForkWithFrameRateQuality(4000, ResampleMedium)
+	assert.Nil(t, err)
+	assert.True(t, downsampled.DBFS() < -40)
+
+	downsampled, err = seg.ForkWithFrameRateQuality(4000, ResampleLinear)
+	assert.Nil(t, err)
+	assert.True(t, downsampled.DBFS() > -20)
+}
+
+func TestSyncResampleQuality(t *testing.T) {
+	low := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2)
+	high, err := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 1, 2).ForkWithFrameRate(16000)
+	assert.Nil(t, err)
+
+	linear, err := low.ForkWithFrameRateQuality(16000, ResampleLinear)
+	assert.Nil(t, err)
+	sinc, err := low.ForkWithFrameRate(16000)
+	assert.Nil(t, err)
+
+	combined, err := low.AppendWithConfig(&AppendConfig{ResampleQuality: ResampleLinear}, high)
+	assert.Nil(t, err)
+	assert.Equal(t, linear.RawData(), combined.RawData()[:len(linear.RawData())])
+
+	combined, err = low.Append(high)
+	assert.Nil(t, err)
+	assert.Equal(t, sinc.RawData(), combined.RawData()[:len(sinc.RawData())])
+
+	overlaid, err := low.Overlay(high, &OverlayConfig{ResampleQuality: ResampleLinear})
+	assert.Nil(t, err)
+	expected, err := linear.Overlay(high, &OverlayConfig{})
+	assert.Nil(t, err)
+	assert.True(t, expected.Equal(overlaid))
+}
@@ -0,0 +1,277 @@
+package godub
+
+import (
+	"math"
+
+	"github.com/iFaceless/godub/audioop"
+)
+
+// channelSamples decodes the raw data into one slice of samples per channel.
+// Samples are normalised to the range [-1, 1), float samples are kept as is.
+func (seg *AudioSegment) channelSamples() ([][]float64, error) {
+	channels := int(seg.channels)
+	frameCount := int(seg.FrameCount())
+	sampleAt := seg.sampleReader()
+
+	result := make([][]float64, channels)
+	for c := range result {
+		result[c] = make([]float64, frameCount)
+	}
+
+	for i := 0; i < frameCount; i++ {
+		for c := 0; c < channels; c++ {
+			sample, err := sampleAt(i*channels + c)
+			if err != nil {
+				return nil, err
+			}
+			result[c][i] = sample
+		}
+	}
+
+	return result, nil
+}
+
+// sampleReader returns a function reading the normalised sample at an index of the interleaved
+// data, for callers that go through the samples once and don't need them all in memory.
+func (seg *AudioSegment) sampleReader() func(index int) (float64, error) {
+	size := int(seg.sampleWidth)
+	maxAmplitude := seg.MaxPossibleAmplitude()
+
+	return func(index int) (float64, error) {
+		if seg.sampleFormat == SampleFormatFloat {
+			return getFloatSample(seg.data, size, index)
+		}
+
+		sample, err := audioop.GetSample(seg.data, size, index)
+		if err != nil {
+			return 0, err
+		}
+
+		// 8-bit audio is stored as unsigned data.
+		if size == 1 {
+			sample = int32(uint8(sample)) - 128
+		}
+		return float64(sample) / maxAmplitude, nil
+	}
+}
+
+// deriveFromChannelSamples creates a new audio segment from normalised per-channel samples,
+// keeping the sample format and frame rate of the current one unless they are overridden by
+// `opts`. Out of range samples are clipped, except for float samples.
+func (seg *AudioSegment) deriveFromChannelSamples(samples [][]float64, opts ...AudioSegmentOption) (*AudioSegment, error) {
+	if len(samples) == 0 {
+		return nil, NewAudioSegmentError("at least one channel is required")
+	}
+
+	channels := len(samples)
+	frameCount := len(samples[0])
+	for _, s := range samples {
+		if len(s) != frameCount {
+			return nil, NewAudioSegmentError("all channels should have the same length")
+		}
+	}
+
+	target, err := seg.derive(nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(target.sampleWidth)
+	maxAmplitude := target.MaxPossibleAmplitude()
+	minValue, maxValue := -maxAmplitude, maxAmplitude-1
+
+	data := make([]byte, frameCount*channels*size)
+	for i := 0; i < frameCount; i++ {
+		for c := 0; c < channels; c++ {
+			if target.sampleFormat == SampleFormatFloat {
+				err := putFloatSample(data, size, i*channels+c, samples[c][i])
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			v := math.Round(samples[c][i] * maxAmplitude)
+			v = math.Max(minValue, math.Min(maxValue, v))
+
+			sample := int32(v)
+			if size == 1 {
+				sample = int32(int8(uint8(sample + 128)))
+			}
+
+			err := audioop.PutSample(data, size, i*channels+c, sample)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return target.derive(data, Channels(uint16(channels)), FrameWidth(uint32(channels*size)))
+}
+
+// GetArrayOfSamples returns the interleaved samples, e.g. left, right, left, right for stereo
+// audio. 8-bit samples are centred on 0 like other sample widths, float audio isn't supported.
+func (seg *AudioSegment) GetArrayOfSamples() ([]int32, error) {
+	if seg.sampleFormat == SampleFormatFloat {
+		return nil, NewAudioSegmentError("float samples can't be returned as integers, use GetFloatSamples")
+	}
+
+	data, err := seg.signedData()
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(seg.sampleWidth)
+	count := int(seg.FrameCount()) * int(seg.channels)
+	samples := make([]int32, count)
+	for i := range samples {
+		samples[i], err = audioop.GetSample(data, size, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samples, nil
+}
+
+// GetChannelSamples returns the samples of each channel, see GetArrayOfSamples.
+func (seg *AudioSegment) GetChannelSamples() ([][]int32, error) {
+	samples, err := seg.GetArrayOfSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	channels := int(seg.channels)
+	result := make([][]int32, channels)
+	for c := range result {
+		result[c] = make([]int32, len(samples)/channels)
+	}
+	for i, v := range samples {
+		result[i%channels][i/channels] = v
+	}
+	return result, nil
+}
+
+// GetFloatSamples returns the interleaved samples normalised to the range [-1, 1).
+// Float samples are returned as is, and may go beyond that range.
+func (seg *AudioSegment) GetFloatSamples() ([]float64, error) {
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+	return interleave(samples), nil
+}
+
+// GetChannelFloatSamples returns the samples of each channel, see GetFloatSamples.
+func (seg *AudioSegment) GetChannelFloatSamples() ([][]float64, error) {
+	return seg.channelSamples()
+}
+
+// NewAudioSegmentFromSamples creates an audio segment from interleaved samples, e.g. left,
+// right, left, right for stereo audio. 8-bit samples should be centred on 0 like other sample
+// widths, out of range samples are clipped.
+func NewAudioSegmentFromSamples(samples []int32, sampleWidth uint16, frameRate uint32, channels uint16) (*AudioSegment, error) {
+	if sampleWidth < 1 || sampleWidth > 4 {
+		return nil, NewAudioSegmentError("sample width should be 1, 2, 3 or 4, got %d", sampleWidth)
+	}
+
+	if channels < 1 || len(samples)%int(channels) != 0 {
+		return nil, NewAudioSegmentError("not a whole number of frames")
+	}
+
+	size := int(sampleWidth)
+	maxValue := int32(1<<uint(8*size-1) - 1)
+	minValue := -maxValue - 1
+
+	data := make([]byte, len(samples)*size)
+	for i, v := range samples {
+		if v > maxValue {
+			v = maxValue
+		} else if v < minValue {
+			v = minValue
+		}
+
+		if size == 1 {
+			// 8-bit audio is stored as unsigned data.
+			v = int32(int8(uint8(v + 128)))
+		}
+
+		err := audioop.PutSample(data, size, i, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewAudioSegment(
+		data,
+		SampleWidth(sampleWidth),
+		FrameRate(frameRate),
+		FrameWidth(uint32(channels)*uint32(sampleWidth)),
+		Channels(channels),
+	)
+}
+
+// NewAudioSegmentFromChannelSamples creates an audio segment from the samples of each channel,
+// see NewAudioSegmentFromSamples.
+func NewAudioSegmentFromChannelSamples(samples [][]int32, sampleWidth uint16, frameRate uint32) (*AudioSegment, error) {
+	if len(samples) == 0 {
+		return nil, NewAudioSegmentError("at least one channel is required")
+	}
+
+	channels := len(samples)
+	frameCount := len(samples[0])
+	for _, channel := range samples {
+		if len(channel) != frameCount {
+			return nil, NewAudioSegmentError("all channels should have the same length")
+		}
+	}
+
+	interleaved := make([]int32, 0, frameCount*channels)
+	for i := 0; i < frameCount; i++ {
+		for _, channel := range samples {
+			interleaved = append(interleaved, channel[i])
+		}
+	}
+
+	return NewAudioSegmentFromSamples(interleaved, sampleWidth, frameRate, uint16(channels))
+}
+
+// NewAudioSegmentFromFloatSamples creates a 32-bit float audio segment from interleaved samples,
+// where full scale is 1. Use ForkWithSampleFormat to convert it to PCM.
+func NewAudioSegmentFromFloatSamples(samples []float64, frameRate uint32, channels uint16) (*AudioSegment, error) {
+	if channels < 1 || len(samples)%int(channels) != 0 {
+		return nil, NewAudioSegmentError("not a whole number of frames")
+	}
+
+	result := make([][]float64, channels)
+	for c := range result {
+		result[c] = make([]float64, len(samples)/int(channels))
+	}
+	for i, v := range samples {
+		result[i%int(channels)][i/int(channels)] = v
+	}
+
+	return NewAudioSegmentFromChannelFloatSamples(result, frameRate)
+}
+
+// NewAudioSegmentFromChannelFloatSamples creates a 32-bit float audio segment from the samples
+// of each channel, see NewAudioSegmentFromFloatSamples.
+func NewAudioSegmentFromChannelFloatSamples(samples [][]float64, frameRate uint32) (*AudioSegment, error) {
+	seg := &AudioSegment{sampleWidth: 4, frameRate: frameRate, sampleFormat: SampleFormatFloat}
+	return seg.deriveFromChannelSamples(samples)
+}
+
+// interleave merges per-channel samples into frames.
+func interleave(samples [][]float64) []float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	channels := len(samples)
+	result := make([]float64, len(samples[0])*channels)
+	for c, channel := range samples {
+		for i, v := range channel {
+			result[i*channels+c] = v
+		}
+	}
+	return result
+}
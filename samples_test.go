@@ -0,0 +1,93 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetArrayOfSamples(t *testing.T) {
+	seg, err := NewAudioSegmentFromSamples([]int32{1, -2, 3, -4, 5, -6}, 2, 8000, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1, 0, 0xfe, 0xff, 3, 0, 0xfc, 0xff, 5, 0, 0xfa, 0xff}, seg.RawData())
+	assert.Equal(t, uint32(4), seg.FrameWidth())
+	assert.Equal(t, float64(3), seg.FrameCount())
+
+	samples, err := seg.GetArrayOfSamples()
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, -2, 3, -4, 5, -6}, samples)
+
+	channels, err := seg.GetChannelSamples()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int32{{1, 3, 5}, {-2, -4, -6}}, channels)
+
+	fromChannels, err := NewAudioSegmentFromChannelSamples(channels, 2, 8000)
+	assert.Nil(t, err)
+	assert.True(t, seg.Equal(fromChannels))
+}
+
+func TestSamples8Bit(t *testing.T) {
+	// 8-bit samples are centred on 0, out of range samples are clipped.
+	seg, err := NewAudioSegmentFromSamples([]int32{-128, 0, 127, 1000}, 1, 8000, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x00, 0x80, 0xff, 0xff}, seg.RawData())
+
+	samples, err := seg.GetArrayOfSamples()
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{-128, 0, 127, 127}, samples)
+
+	_, err = NewAudioSegmentFromSamples([]int32{1, 2, 3}, 2, 8000, 2)
+	assert.Error(t, err)
+	_, err = NewAudioSegmentFromSamples([]int32{1}, 5, 8000, 1)
+	assert.Error(t, err)
+	_, err = NewAudioSegmentFromChannelSamples([][]int32{{1, 2}, {1}}, 2, 8000)
+	assert.Error(t, err)
+
+	_, err = NewAudioSegmentFromChannelSamples([][]int32{{}, {1, 2}}, 2, 8000)
+	assert.Error(t, err)
+}
+
+func TestGetFloatSamples(t *testing.T) {
+	seg, err := NewAudioSegmentFromSamples([]int32{0x4000, -0x8000, 0, 0x2000}, 2, 8000, 2)
+	assert.Nil(t, err)
+
+	samples, err := seg.GetFloatSamples()
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.5, -1, 0, 0.25}, samples)
+
+	channels, err := seg.GetChannelFloatSamples()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]float64{{0.5, 0}, {-1, 0.25}}, channels)
+
+	float, err := NewAudioSegmentFromFloatSamples(samples, 8000, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, SampleFormatFloat, float.SampleFormat())
+	assert.Equal(t, uint16(4), float.SampleWidth())
+	assert.Equal(t, uint16(2), float.Channels())
+
+	fromChannels, err := NewAudioSegmentFromChannelFloatSamples(channels, 8000)
+	assert.Nil(t, err)
+	assert.True(t, float.Equal(fromChannels))
+
+	pcm, err := float.ForkWithSampleFormat(SampleFormatPCM, 2)
+	assert.Nil(t, err)
+	assert.True(t, seg.Equal(pcm))
+
+	_, err = float.GetArrayOfSamples()
+	assert.Error(t, err)
+}
+
+func TestFloatSamplesRoundTrip(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 3)
+
+	samples, err := seg.GetChannelFloatSamples()
+	assert.Nil(t, err)
+	float, err := NewAudioSegmentFromChannelFloatSamples(samples, seg.FrameRate())
+	assert.Nil(t, err)
+	assert.Equal(t, seg.Duration(), float.Duration())
+
+	pcm, err := float.ForkWithSampleFormat(SampleFormatPCM, 3)
+	assert.Nil(t, err)
+	assert.True(t, seg.Equal(pcm))
+}
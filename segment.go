@@ -6,8 +6,6 @@ import (
 
 	"fmt"
 
-	"encoding/binary"
-
 	"time"
 
 	"github.com/iFaceless/godub/audioop"
@@ -16,6 +14,7 @@ import (
 )
 
 var (
+	// Deprecated: ForkWithChannels accepts any number of channels >= 1.
 	ValidChannels = utils.NewSet(1, 2)
 )
 
@@ -27,7 +26,9 @@ type AudioSegment struct {
 	frameRate   uint32
 	frameWidth  uint32
 	channels    uint16
-	data        []byte
+	// sampleFormat tells whether samples are integers or floats.
+	sampleFormat SampleFormat
+	data         []byte
 
 	// Cached values, because audio segment is immutable
 	// it's safe to store it.
@@ -49,30 +50,6 @@ func NewAudioSegment(data []byte, opts ...AudioSegmentOption) (*AudioSegment, er
 	}
 
 	// FIXME: check if sample params are all valid or not.
-	// Convert 24-bit audio to 32-bit audio. Package audioop only supports 32-bit data.
-	if seg.sampleWidth == 3 {
-		bytesLen := len(data)/int(seg.sampleWidth) + len(data)
-		buf := make([]byte, bytesLen)
-
-		offset := 0
-		for i := 0; i < len(data); i += 3 {
-			b0, b1, b2 := data[i], data[i+1], data[i+2]
-
-			var padding byte
-			if b2 > 127 {
-				padding = 0xFF
-			}
-
-			w := bytes.NewBuffer(buf[offset:])
-			binary.Write(w, binary.LittleEndian, []byte{padding, b0, b1, b2})
-
-			// Next available position to write
-			offset += 4
-		}
-
-		seg.data = buf
-		seg.sampleWidth = 4
-	}
 	return seg, nil
 }
 
@@ -100,18 +77,29 @@ func NewSilentAudioSegment(duration int, frameRate uint32) (*AudioSegment, error
 
 func NewAudioSegmentFromWaveAudio(waveAudio *wav.WaveAudio) (*AudioSegment, error) {
 	sampleWidth := waveAudio.BitsPerSample / 8
+	format := SampleFormatPCM
+	if waveAudio.Format == wav.AudioFormatIEEEFloat {
+		format = SampleFormatFloat
+	}
+
 	return NewAudioSegment(
 		waveAudio.RawData,
 		Channels(waveAudio.Channels),
 		SampleWidth(sampleWidth),
 		FrameRate(waveAudio.SampleRate),
 		FrameWidth(uint32(waveAudio.Channels*sampleWidth)),
+		Format(format),
 	)
 }
 
 func (seg *AudioSegment) AsWaveAudio() *wav.WaveAudio {
+	format := uint16(wav.AudioFormatPCM)
+	if seg.sampleFormat == SampleFormatFloat {
+		format = wav.AudioFormatIEEEFloat
+	}
+
 	waveAudio := wav.WaveAudio{
-		Format:        wav.AudioFormatPCM,
+		Format:        format,
 		Channels:      seg.channels,
 		RawData:       seg.data,
 		BitsPerSample: seg.sampleWidth * 8,
@@ -172,10 +160,14 @@ func (seg *AudioSegment) Add(other *AudioSegment) (*AudioSegment, error) {
 }
 
 func (seg *AudioSegment) Append(segments ...*AudioSegment) (*AudioSegment, error) {
+	return seg.append(ResampleDefault, segments)
+}
+
+func (seg *AudioSegment) append(quality ResampleQuality, segments []*AudioSegment) (*AudioSegment, error) {
 	combined := []*AudioSegment{seg}
 	combined = append(combined, segments...)
 
-	results, err := sync(combined...)
+	results, err := sync(quality, combined...)
 	if err != nil {
 		return nil, err
 	}
@@ -184,19 +176,90 @@ func (seg *AudioSegment) Append(segments ...*AudioSegment) (*AudioSegment, error
 	for _, r := range results {
 		data = append(data, r.data)
 	}
-	return seg.derive(utils.ConcatenateByteSlice(data...))
+	return results[0].derive(utils.ConcatenateByteSlice(data...))
 }
 
-func (seg *AudioSegment) Equal(other *AudioSegment) bool {
-	return bytes.Equal(seg.data, other.data)
+type AppendConfig struct {
+	// Crossfade is the length of the overlap between two adjacent segments.
+	Crossfade time.Duration
+	// CrossfadeCurve is the curve used to fade in the next segment,
+	// the previous one fades out with the mirrored curve. Default to FadeLinear.
+	CrossfadeCurve FadeCurve
+	// ResampleQuality is used to sync the frame rates of the segments,
+	// ResampleLinear gives pydub compatible output. Default to ResampleDefault.
+	ResampleQuality ResampleQuality
 }
 
-func (seg *AudioSegment) ApplyGain(volumeChange Volume) (*AudioSegment, error) {
-	data, err := audioop.Mul(seg.data, int(seg.sampleWidth), volumeChange.ToRatio(true))
+// AppendWithCrossfade appends segments, overlapping each join by `crossfade` with equal-power curves.
+func (seg *AudioSegment) AppendWithCrossfade(crossfade time.Duration, segments ...*AudioSegment) (*AudioSegment, error) {
+	return seg.AppendWithConfig(&AppendConfig{Crossfade: crossfade, CrossfadeCurve: FadeEqualPower}, segments...)
+}
+
+// AppendWithConfig appends segments, the tail of each segment is crossfaded
+// with the head of the next one.
+func (seg *AudioSegment) AppendWithConfig(config *AppendConfig, segments ...*AudioSegment) (*AudioSegment, error) {
+	if config.Crossfade < 0 {
+		return nil, NewAudioSegmentError("crossfade should be positive")
+	}
+
+	if config.Crossfade == 0 {
+		return seg.append(config.ResampleQuality, segments)
+	}
+
+	combined := []*AudioSegment{seg}
+	combined = append(combined, segments...)
+
+	results, err := sync(config.ResampleQuality, combined...)
 	if err != nil {
 		return nil, err
 	}
-	return seg.derive(data)
+
+	first := results[0]
+	if config.Crossfade > first.Duration() {
+		return nil, NewAudioSegmentError("crossfade is longer than the original segment")
+	}
+
+	data, err := first.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results[1:] {
+		if config.Crossfade > r.Duration() {
+			return nil, NewAudioSegmentError("crossfade is longer than the appended segment")
+		}
+
+		next, err := r.channelSamples()
+		if err != nil {
+			return nil, err
+		}
+
+		overlap := r.parsePosition(config.Crossfade)
+		if overlap > len(data[0]) {
+			overlap = len(data[0])
+		}
+
+		for c := range data {
+			head := len(data[c]) - overlap
+			for i := 0; i < overlap; i++ {
+				progress := float64(i) / float64(overlap)
+				fadeOut := config.CrossfadeCurve.shape(1 - progress)
+				fadeIn := config.CrossfadeCurve.shape(progress)
+				data[c][head+i] = data[c][head+i]*fadeOut + next[c][i]*fadeIn
+			}
+			data[c] = append(data[c], next[c][overlap:]...)
+		}
+	}
+
+	return first.deriveFromChannelSamples(data)
+}
+
+func (seg *AudioSegment) Equal(other *AudioSegment) bool {
+	return bytes.Equal(seg.data, other.data)
+}
+
+func (seg *AudioSegment) ApplyGain(volumeChange Volume) (*AudioSegment, error) {
+	return seg.mul(volumeChange.ToRatio(true))
 }
 
 func (seg *AudioSegment) Repeat(count int) (*AudioSegment, error) {
@@ -204,6 +267,20 @@ func (seg *AudioSegment) Repeat(count int) (*AudioSegment, error) {
 }
 
 func (seg *AudioSegment) Reverse() (*AudioSegment, error) {
+	if seg.sampleFormat == SampleFormatFloat {
+		samples, err := seg.channelSamples()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, channel := range samples {
+			for i, j := 0, len(channel)-1; i < j; i, j = i+1, j-1 {
+				channel[i], channel[j] = channel[j], channel[i]
+			}
+		}
+		return seg.deriveFromChannelSamples(samples)
+	}
+
 	data, err := audioop.Reverse(seg.data, int(seg.sampleWidth))
 	if err != nil {
 		return nil, err
@@ -216,6 +293,10 @@ func (seg *AudioSegment) ForkWithSampleWidth(sampleWidth int) (*AudioSegment, er
 		return seg, nil
 	}
 
+	if seg.sampleFormat == SampleFormatFloat {
+		return seg.ForkWithSampleFormat(SampleFormatFloat, sampleWidth)
+	}
+
 	data := seg.data
 
 	if seg.sampleWidth == 1 {
@@ -246,15 +327,42 @@ func (seg *AudioSegment) ForkWithSampleWidth(sampleWidth int) (*AudioSegment, er
 	return seg.derive(data, SampleWidth(uint16(sampleWidth)), FrameWidth(uint32(frameWidth)))
 }
 
+// ForkWithFrameRate resamples the audio to the given frame rate with ResampleDefault.
 func (seg *AudioSegment) ForkWithFrameRate(frameRate int) (*AudioSegment, error) {
+	return seg.ForkWithFrameRateQuality(frameRate, ResampleDefault)
+}
+
+// ForkWithFrameRateQuality resamples the audio to the given frame rate with the given quality.
+// Float audio is always resampled with a windowed-sinc filter, ResampleLinear uses ResampleMedium.
+func (seg *AudioSegment) ForkWithFrameRateQuality(frameRate int, quality ResampleQuality) (*AudioSegment, error) {
 	if frameRate == int(seg.frameRate) {
 		return seg, nil
 	}
 
-	converted := seg.data
-	if len(seg.data) > 0 {
+	if frameRate <= 0 || seg.frameRate == 0 {
+		return nil, NewAudioSegmentError("frame rate should be > 0")
+	}
+
+	if quality != ResampleLinear || seg.sampleFormat == SampleFormatFloat {
+		samples, err := seg.channelSamples()
+		if err != nil {
+			return nil, err
+		}
+
+		for c, channel := range samples {
+			samples[c] = resample(channel, int(seg.frameRate), frameRate, quality)
+		}
+		return seg.deriveFromChannelSamples(samples, FrameRate(uint32(frameRate)))
+	}
+
+	converted, err := seg.signedData()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(converted) > 0 {
 		ret, _, err := audioop.Ratecv(
-			seg.data,
+			converted,
 			int(seg.sampleWidth),
 			int(seg.channels),
 			int(seg.frameRate),
@@ -268,11 +376,24 @@ func (seg *AudioSegment) ForkWithFrameRate(frameRate int) (*AudioSegment, error)
 		converted = ret
 	}
 
+	if seg.sampleWidth == 1 {
+		converted, err = audioop.Bias(converted, 1, 128)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return seg.derive(converted, FrameRate(uint32(frameRate)))
 }
 
+// ForkWithChannels mixes the audio into the given number of channels. Mono, stereo, 5.1
+// and 7.1 layouts are converted with the standard matrices such as Surround51ToStereo,
+// other layouts are averaged when mixed down to mono and copied when mixed up from mono.
+// Between other layouts the shared channels are kept, extra output channels are silent
+// and extra input channels are folded into the others at -3dB. Downmixes sum channels
+// without headroom, so loud PCM audio may clip; convert it to float first to avoid it.
 func (seg *AudioSegment) ForkWithChannels(channels uint16) (*AudioSegment, error) {
-	if !ValidChannels.Has(int(channels)) {
+	if channels < 1 {
 		return nil, NewAudioSegmentError("invalid channels")
 	}
 
@@ -281,26 +402,11 @@ func (seg *AudioSegment) ForkWithChannels(channels uint16) (*AudioSegment, error
 		return seg, nil
 	}
 
-	var convertFunc func(cp []byte, size int, fac1, fac2 float64) ([]byte, error)
-	var frameWidth int
-	var fac float64
-
-	if channels == 2 && seg.channels == 1 {
-		convertFunc = audioop.ToStereo
-		frameWidth = int(seg.frameWidth) * 2
-		fac = 1
-	} else if channels == 1 && seg.channels == 2 {
-		convertFunc = audioop.ToMono
-		frameWidth = int(seg.frameWidth) / 2
-		fac = 0.5
-	}
-
-	converted, err := convertFunc(seg.data, int(seg.sampleWidth), fac, fac)
+	matrix, err := standardChannelMatrix(int(seg.channels), int(channels))
 	if err != nil {
 		return nil, err
 	}
-
-	return seg.derive(converted, Channels(channels), FrameWidth(uint32(frameWidth)))
+	return seg.ForkWithChannelMatrix(matrix)
 }
 
 type OverlayConfig struct {
@@ -312,6 +418,9 @@ type OverlayConfig struct {
 	// until it matches the original segment length, default to 1.
 	LoopCount         int
 	GainDuringOverlay Volume
+	// ResampleQuality is used to sync the frame rates of the segments,
+	// ResampleLinear gives pydub compatible output. Default to ResampleDefault.
+	ResampleQuality ResampleQuality
 }
 
 // Overlay overlays the given audio segment on the current segment.
@@ -329,7 +438,7 @@ func (seg *AudioSegment) Overlay(other *AudioSegment, config *OverlayConfig) (*A
 		config.LoopCount = -1
 	}
 
-	syncedSegments, err := sync(seg, other)
+	syncedSegments, err := sync(config.ResampleQuality, seg, other)
 	if err != nil {
 		return nil, err
 	}
@@ -354,7 +463,6 @@ func (seg *AudioSegment) Overlay(other *AudioSegment, config *OverlayConfig) (*A
 		return nil, err
 	}
 
-	sampleWidth := int(segment.sampleWidth)
 	rSegLen := len(rSegment.data)
 	rSegData := rSegment.data
 
@@ -377,23 +485,22 @@ func (seg *AudioSegment) Overlay(other *AudioSegment, config *OverlayConfig) (*A
 
 		var overlaidBytes []byte
 		if config.GainDuringOverlay > 0 {
-			adjustedBytes, err := audioop.Mul(
+			adjustedBytes, err := segment.mulData(
 				rSegData[pos:pos+otherSegLen],
-				sampleWidth,
 				config.GainDuringOverlay.ToRatio(true),
 			)
 			if err != nil {
 				return nil, err
 			}
 
-			r, err := audioop.Add(adjustedBytes, otherSegData, sampleWidth)
+			r, err := segment.addData(adjustedBytes, otherSegData)
 			if err != nil {
 				return nil, err
 			}
 
 			overlaidBytes = r
 		} else {
-			r, err := audioop.Add(rSegData[pos:pos+otherSegLen], otherSegData, sampleWidth)
+			r, err := segment.addData(rSegData[pos:pos+otherSegLen], otherSegData)
 			if err != nil {
 				return nil, err
 			}
@@ -424,22 +531,41 @@ func (seg *AudioSegment) RMS() float64 {
 		return *seg.rms
 	}
 
-	if seg.sampleWidth == 1 {
-		if r, err := seg.ForkWithSampleWidth(2); err != nil {
-			return 0
-		} else {
-			return r.RMS()
-		}
-	} else {
-		r, err := audioop.RMS(seg.data, int(seg.sampleWidth))
+	if seg.sampleFormat == SampleFormatFloat {
+		samples, err := seg.channelSamples()
 		if err != nil {
 			return 0
 		}
-		rms := float64(r)
+
+		var sum float64
+		var count int
+		for _, channel := range samples {
+			for _, v := range channel {
+				sum += v * v
+			}
+			count += len(channel)
+		}
+
+		var rms float64
+		if count > 0 {
+			rms = math.Sqrt(sum / float64(count))
+		}
 		seg.rms = &rms
 		return rms
 	}
-	return 0
+
+	data, err := seg.signedData()
+	if err != nil {
+		return 0
+	}
+
+	r, err := audioop.RMS(data, int(seg.sampleWidth))
+	if err != nil {
+		return 0
+	}
+	rms := float64(r)
+	seg.rms = &rms
+	return rms
 }
 
 // DBFS returns the value of dB Full Scale
@@ -447,7 +573,12 @@ func (seg *AudioSegment) DBFS() Volume {
 	return NewVolumeFromRatio(seg.RMS()/seg.MaxPossibleAmplitude(), 0, true)
 }
 
+// MaxPossibleAmplitude returns the full scale amplitude, 1 for float samples.
 func (seg *AudioSegment) MaxPossibleAmplitude() float64 {
+	if seg.sampleFormat == SampleFormatFloat {
+		return 1
+	}
+
 	bits := seg.sampleWidth * 8
 	maxPossibleVal := math.Pow(2, float64(bits))
 	// Since half is above 0 and half is below the max amplitude is divided
@@ -459,7 +590,27 @@ func (seg *AudioSegment) MaxDBFS() Volume {
 }
 
 func (seg *AudioSegment) Max() float64 {
-	if r, err := audioop.Max(seg.data, int(seg.sampleWidth)); err != nil {
+	if seg.sampleFormat == SampleFormatFloat {
+		samples, err := seg.channelSamples()
+		if err != nil {
+			return 0
+		}
+
+		var max float64
+		for _, channel := range samples {
+			for _, v := range channel {
+				max = math.Max(max, math.Abs(v))
+			}
+		}
+		return max
+	}
+
+	data, err := seg.signedData()
+	if err != nil {
+		return 0
+	}
+
+	if r, err := audioop.Max(data, int(seg.sampleWidth)); err != nil {
 		return 0
 	} else {
 		return float64(r)
@@ -509,26 +660,41 @@ func (seg *AudioSegment) Channels() uint16 {
 	return seg.channels
 }
 
+func (seg *AudioSegment) SampleFormat() SampleFormat {
+	return seg.sampleFormat
+}
+
 func (seg *AudioSegment) RawData() []byte {
 	return seg.data
 }
 
 // Private functions & methods
-// sync will make sure every input segments have identical channels, frame rate and sample width.
-func sync(segments ...*AudioSegment) ([]*AudioSegment, error) {
+// sync will make sure every input segments have identical channels, frame rate and sample format.
+// If any segment has float samples, all segments are converted to float. Frame rates
+// are converted with the given resample quality.
+func sync(quality ResampleQuality, segments ...*AudioSegment) ([]*AudioSegment, error) {
 	allChannels := make([]uint16, 0)
 	allFrameRates := make([]uint32, 0)
 	allSampleWidths := make([]uint16, 0)
+	allFloatWidths := make([]uint16, 0)
 
 	for _, seg := range segments {
 		allChannels = append(allChannels, seg.channels)
 		allFrameRates = append(allFrameRates, seg.frameRate)
 		allSampleWidths = append(allSampleWidths, seg.sampleWidth)
+		if seg.sampleFormat == SampleFormatFloat {
+			allFloatWidths = append(allFloatWidths, seg.sampleWidth)
+		}
 	}
 
 	maxChannels := utils.MaxUint16(allChannels...)
 	maxFrameRate := utils.MaxUint32(allFrameRates...)
 	maxSampleWidth := utils.MaxUint16(allSampleWidths...)
+	format := SampleFormatPCM
+	if len(allFloatWidths) > 0 {
+		format = SampleFormatFloat
+		maxSampleWidth = utils.MaxUint16(allFloatWidths...)
+	}
 
 	newSegments := make([]*AudioSegment, 0)
 	for _, seg := range segments {
@@ -539,13 +705,13 @@ func sync(segments ...*AudioSegment) ([]*AudioSegment, error) {
 			newSeg = r
 		}
 
-		if r, err := newSeg.ForkWithFrameRate(int(maxFrameRate)); err != nil {
+		if r, err := newSeg.ForkWithFrameRateQuality(int(maxFrameRate), quality); err != nil {
 			return nil, err
 		} else {
 			newSeg = r
 		}
 
-		if r, err := newSeg.ForkWithSampleWidth(int(maxSampleWidth)); err != nil {
+		if r, err := newSeg.ForkWithSampleFormat(format, int(maxSampleWidth)); err != nil {
 			return nil, err
 		} else {
 			newSeg = r
@@ -565,6 +731,7 @@ func (seg *AudioSegment) derive(data []byte, opts ...AudioSegmentOption) (*Audio
 		FrameRate(seg.frameRate),
 		FrameWidth(seg.frameWidth),
 		Channels(seg.channels),
+		Format(seg.sampleFormat),
 	)
 	if err != nil {
 		return nil, err
@@ -577,6 +744,87 @@ func (seg *AudioSegment) derive(data []byte, opts ...AudioSegmentOption) (*Audio
 	return ret, nil
 }
 
+// mul multiplies all samples by factor, samples out of range are clipped.
+func (seg *AudioSegment) mul(factor float64) (*AudioSegment, error) {
+	if seg.sampleFormat == SampleFormatFloat {
+		data, err := seg.mulData(seg.data, factor)
+		if err != nil {
+			return nil, err
+		}
+		return seg.derive(data)
+	}
+
+	data, err := seg.signedData()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = audioop.Mul(data, int(seg.sampleWidth), factor)
+	if err != nil {
+		return nil, err
+	}
+
+	if seg.sampleWidth == 1 {
+		data, err = audioop.Bias(data, 1, 128)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return seg.derive(data)
+}
+
+// mulData multiplies raw samples in the format of the segment by factor.
+func (seg *AudioSegment) mulData(data []byte, factor float64) ([]byte, error) {
+	if seg.sampleFormat != SampleFormatFloat {
+		return audioop.Mul(data, int(seg.sampleWidth), factor)
+	}
+
+	size := int(seg.sampleWidth)
+	buf := make([]byte, len(data))
+	for i := 0; i < len(data)/size; i++ {
+		v, err := getFloatSample(data, size, i)
+		if err != nil {
+			return nil, err
+		}
+		putFloatSample(buf, size, i, v*factor)
+	}
+	return buf, nil
+}
+
+// addData adds raw samples in the format of the segment.
+func (seg *AudioSegment) addData(data1, data2 []byte) ([]byte, error) {
+	if seg.sampleFormat != SampleFormatFloat {
+		return audioop.Add(data1, data2, int(seg.sampleWidth))
+	}
+
+	if len(data1) != len(data2) {
+		return nil, NewAudioSegmentError("lengths should be the same")
+	}
+
+	size := int(seg.sampleWidth)
+	buf := make([]byte, len(data1))
+	for i := 0; i < len(data1)/size; i++ {
+		v1, err := getFloatSample(data1, size, i)
+		if err != nil {
+			return nil, err
+		}
+		v2, err := getFloatSample(data2, size, i)
+		if err != nil {
+			return nil, err
+		}
+		putFloatSample(buf, size, i, v1+v2)
+	}
+	return buf, nil
+}
+
+// signedData returns the raw data as signed samples, 8-bit audio is stored unsigned.
+func (seg *AudioSegment) signedData() ([]byte, error) {
+	if seg.sampleWidth == 1 {
+		return audioop.Bias(seg.data, 1, -128)
+	}
+	return seg.data, nil
+}
+
 func (seg *AudioSegment) parsePosition(val time.Duration) int {
 	frames := seg.FrameCountIn(val)
 	return int(frames)
@@ -0,0 +1,93 @@
+package godub
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/iFaceless/godub/wav"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSineSegment creates a sine wave segment at 8kHz with the given amplitude (0 to 1).
+func newSineSegment(t *testing.T, freq, amplitude float64, duration time.Duration, channels, sampleWidth uint16) *AudioSegment {
+	frameRate := uint32(8000)
+	seg := &AudioSegment{sampleWidth: sampleWidth, frameRate: frameRate, channels: channels}
+
+	frames := int(duration.Seconds() * float64(frameRate))
+	samples := make([][]float64, channels)
+	for c := range samples {
+		samples[c] = make([]float64, frames)
+		for i := range samples[c] {
+			samples[c][i] = amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(frameRate))
+		}
+	}
+
+	ret, err := seg.deriveFromChannelSamples(samples)
+	assert.Nil(t, err)
+	return ret
+}
+
+func TestChannelSamplesRoundTrip(t *testing.T) {
+	for _, width := range []uint16{1, 2, 3, 4} {
+		seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, width)
+		assert.Equal(t, 100*time.Millisecond, seg.Duration())
+
+		samples, err := seg.channelSamples()
+		assert.Nil(t, err)
+
+		derived, err := seg.deriveFromChannelSamples(samples)
+		assert.Nil(t, err)
+		assert.True(t, seg.Equal(derived))
+	}
+}
+
+func Test24BitRoundTrip(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, 100*time.Millisecond, 2, 3)
+	assert.Equal(t, uint16(3), seg.SampleWidth())
+	assert.Equal(t, uint32(6), seg.FrameWidth())
+	assert.InDelta(t, -9.03, float64(seg.DBFS()), 0.01)
+
+	edited, err := seg.Slice(10*time.Millisecond, 60*time.Millisecond)
+	assert.Nil(t, err)
+	edited, err = edited.Append(seg)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(3), edited.SampleWidth())
+
+	buf := bytes.Buffer{}
+	assert.Nil(t, wav.Encode(&buf, edited.AsWaveAudio()))
+	waveAudio, err := wav.Decode(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(24), waveAudio.BitsPerSample)
+
+	loaded, err := NewAudioSegmentFromWaveAudio(waveAudio)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(3), loaded.SampleWidth())
+	assert.True(t, edited.Equal(loaded))
+
+	// Converting to 16-bit and back only loses the lowest byte.
+	reduced, err := seg.ForkWithSampleWidth(2)
+	assert.Nil(t, err)
+	widened, err := reduced.ForkWithSampleWidth(3)
+	assert.Nil(t, err)
+	for i := 0; i < len(seg.RawData()); i += 3 {
+		assert.Equal(t, seg.RawData()[i+1:i+3], widened.RawData()[i+1:i+3])
+	}
+}
+
+func TestAppendWithCrossfade(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 2, 2)
+	other := newSineSegment(t, 220, 0.5, 500*time.Millisecond, 2, 2)
+
+	combined, err := seg.AppendWithCrossfade(100*time.Millisecond, other, other)
+	assert.Nil(t, err)
+	assert.Equal(t, 1800*time.Millisecond, combined.Duration())
+
+	combined, err = seg.AppendWithConfig(&AppendConfig{}, other)
+	assert.Nil(t, err)
+	assert.Equal(t, 1500*time.Millisecond, combined.Duration())
+
+	_, err = seg.AppendWithCrossfade(600*time.Millisecond, other)
+	assert.Error(t, err)
+}
@@ -0,0 +1,195 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+// TimeRange is a range of time inside an audio segment.
+type TimeRange struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+type SilenceConfig struct {
+	// MinSilenceLen is the minimum length of a silence, default to 1s.
+	MinSilenceLen time.Duration
+	// SilenceThresh is the upper bound of how quiet is silence, default to -16dBFS when nil.
+	SilenceThresh *Volume
+	// SeekStep is the step between two windows to check, default to 1ms.
+	SeekStep time.Duration
+}
+
+func (config SilenceConfig) withDefaults() SilenceConfig {
+	if config.MinSilenceLen == 0 {
+		config.MinSilenceLen = time.Second
+	}
+
+	if config.SilenceThresh == nil {
+		config.SilenceThresh = VolumePtr(-16)
+	}
+
+	if config.SeekStep == 0 {
+		config.SeekStep = time.Millisecond
+	}
+	return config
+}
+
+// DetectSilence returns the ranges of silence which are at least `MinSilenceLen` long.
+func (seg *AudioSegment) DetectSilence(config *SilenceConfig) ([]TimeRange, error) {
+	c := config.withDefaults()
+	if c.MinSilenceLen < 0 || c.SeekStep < 0 {
+		return nil, NewAudioSegmentError("min silence length and seek step should be positive")
+	}
+
+	audioLength := seg.Duration()
+	if audioLength < c.MinSilenceLen {
+		return []TimeRange{}, nil
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefix sums of squared samples, so that RMS of every window is cheap to compute.
+	frameCount := int(seg.FrameCount())
+	sumSquares := prefixSumOfSquares(samples)
+
+	rms := func(start, end time.Duration) float64 {
+		startFrame := seg.parsePosition(start)
+		endFrame := seg.parsePosition(end)
+		if endFrame > frameCount {
+			endFrame = frameCount
+		}
+
+		count := (endFrame - startFrame) * len(samples)
+		if count <= 0 {
+			return 0
+		}
+		return math.Sqrt(math.Max(0, sumSquares[endFrame]-sumSquares[startFrame]) / float64(count))
+	}
+
+	lastSliceStart := audioLength - c.MinSilenceLen
+	sliceStarts := make([]time.Duration, 0)
+	for start := time.Duration(0); start <= lastSliceStart; start += c.SeekStep {
+		sliceStarts = append(sliceStarts, start)
+	}
+	if lastSliceStart%c.SeekStep != 0 {
+		sliceStarts = append(sliceStarts, lastSliceStart)
+	}
+
+	threshold := c.SilenceThresh.ToRatio(true)
+	silenceStarts := make([]time.Duration, 0)
+	for _, start := range sliceStarts {
+		if rms(start, start+c.MinSilenceLen) <= threshold {
+			silenceStarts = append(silenceStarts, start)
+		}
+	}
+
+	ranges := make([]TimeRange, 0)
+	if len(silenceStarts) == 0 {
+		return ranges, nil
+	}
+
+	// Combine the overlapping silence windows.
+	rangeStart := silenceStarts[0]
+	prev := silenceStarts[0]
+	for _, start := range silenceStarts[1:] {
+		continuous := start == prev+c.SeekStep
+		// Silence windows may overlap even if they are not continuous.
+		hasGap := start > prev+c.MinSilenceLen
+
+		if !continuous && hasGap {
+			ranges = append(ranges, TimeRange{Start: rangeStart, End: prev + c.MinSilenceLen})
+			rangeStart = start
+		}
+		prev = start
+	}
+	ranges = append(ranges, TimeRange{Start: rangeStart, End: prev + c.MinSilenceLen})
+
+	return ranges, nil
+}
+
+// DetectNonsilent returns the ranges between silences.
+func (seg *AudioSegment) DetectNonsilent(config *SilenceConfig) ([]TimeRange, error) {
+	silentRanges, err := seg.DetectSilence(config)
+	if err != nil {
+		return nil, err
+	}
+
+	audioLength := seg.Duration()
+	if audioLength == 0 {
+		return []TimeRange{}, nil
+	}
+
+	if len(silentRanges) == 0 {
+		return []TimeRange{{Start: 0, End: audioLength}}, nil
+	}
+
+	// The whole segment is silent.
+	if silentRanges[0].Start == 0 && silentRanges[0].End == audioLength {
+		return []TimeRange{}, nil
+	}
+
+	ranges := make([]TimeRange, 0)
+	prevEnd := time.Duration(0)
+	for _, r := range silentRanges {
+		if r.Start > prevEnd {
+			ranges = append(ranges, TimeRange{Start: prevEnd, End: r.Start})
+		}
+		prevEnd = r.End
+	}
+
+	if prevEnd < audioLength {
+		ranges = append(ranges, TimeRange{Start: prevEnd, End: audioLength})
+	}
+
+	return ranges, nil
+}
+
+// SplitOnSilence splits the segment into chunks between silences. Each chunk
+// keeps up to `keepSilence` of the surrounding silence, so that it doesn't sound
+// abruptly cut off. Adjacent chunks never overlap.
+func (seg *AudioSegment) SplitOnSilence(config *SilenceConfig, keepSilence time.Duration) ([]*AudioSegment, error) {
+	if keepSilence < 0 {
+		return nil, NewAudioSegmentError("keep silence should be positive")
+	}
+
+	ranges, err := seg.DetectNonsilent(config)
+	if err != nil {
+		return nil, err
+	}
+
+	audioLength := seg.Duration()
+	padded := make([]TimeRange, len(ranges))
+	for i, r := range ranges {
+		start, end := r.Start-keepSilence, r.End+keepSilence
+		if start < 0 {
+			start = 0
+		}
+		if end > audioLength {
+			end = audioLength
+		}
+		padded[i] = TimeRange{Start: start, End: end}
+	}
+
+	for i := 1; i < len(padded); i++ {
+		if padded[i-1].End > padded[i].Start {
+			middle := (ranges[i-1].End + ranges[i].Start) / 2
+			padded[i-1].End = middle
+			padded[i].Start = middle
+		}
+	}
+
+	chunks := make([]*AudioSegment, 0, len(padded))
+	for _, r := range padded {
+		chunk, err := seg.Slice(r.Start, r.End)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
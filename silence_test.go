@@ -0,0 +1,49 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSilence(t *testing.T) {
+	tone := newSineSegment(t, 440, 0.5, time.Second, 1, 2)
+	silence, _ := NewSilentAudioSegment(1500, tone.FrameRate())
+	seg, err := tone.Append(silence, tone)
+	assert.Nil(t, err)
+
+	config := &SilenceConfig{SilenceThresh: VolumePtr(-40), SeekStep: 10 * time.Millisecond}
+	ranges, err := seg.DetectSilence(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []TimeRange{{Start: time.Second, End: 2500 * time.Millisecond}}, ranges)
+
+	ranges, err = seg.DetectNonsilent(config)
+	assert.Nil(t, err)
+	assert.Equal(t, []TimeRange{
+		{Start: 0, End: time.Second},
+		{Start: 2500 * time.Millisecond, End: 3500 * time.Millisecond},
+	}, ranges)
+
+	chunks, err := seg.SplitOnSilence(config, 100*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, 1100*time.Millisecond, chunks[0].Duration())
+	assert.Equal(t, 1100*time.Millisecond, chunks[1].Duration())
+
+	ranges, err = silence.DetectNonsilent(config)
+	assert.Nil(t, err)
+	assert.Empty(t, ranges)
+
+	assert.Equal(t, Volume(-16), *SilenceConfig{}.withDefaults().SilenceThresh)
+	assert.Equal(t, Volume(0), *SilenceConfig{SilenceThresh: VolumePtr(0)}.withDefaults().SilenceThresh)
+
+	empty, err := NewSilentAudioSegment(0, tone.FrameRate())
+	assert.Nil(t, err)
+	ranges, err = empty.DetectNonsilent(config)
+	assert.Nil(t, err)
+	assert.Empty(t, ranges)
+	chunks, err = empty.SplitOnSilence(config, 100*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Empty(t, chunks)
+}
@@ -0,0 +1,149 @@
+package godub
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// wsolaFrameLength is the length of the overlapping frames.
+	wsolaFrameLength = 40 * time.Millisecond
+	// wsolaTolerance is how far a frame may move to line up with the previous one.
+	wsolaTolerance = 10 * time.Millisecond
+	// wsolaSearchRate is the frame rate used for the coarse search of the best alignment.
+	wsolaSearchRate = 8000
+)
+
+// TimeStretch changes the duration of the segment by `factor` without changing
+// the pitch, e.g. 2 makes the segment twice as long. It uses WSOLA (waveform
+// similarity overlap-add), which works best for speech and monophonic music.
+func (seg *AudioSegment) TimeStretch(factor float64) (*AudioSegment, error) {
+	if factor <= 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return nil, NewAudioSegmentError("stretch factor should be > 0")
+	}
+
+	if factor == 1 {
+		return seg, nil
+	}
+
+	samples, err := seg.channelSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	return seg.deriveFromChannelSamples(wsola(samples, seg.frameRate, factor))
+}
+
+// SpeedUp plays the segment `factor` times faster without changing the pitch,
+// a factor below 1 slows it down.
+func (seg *AudioSegment) SpeedUp(factor float64) (*AudioSegment, error) {
+	if factor <= 0 {
+		return nil, NewAudioSegmentError("speed factor should be > 0")
+	}
+	return seg.TimeStretch(1 / factor)
+}
+
+// wsola stretches every channel by `factor`. Frames are chosen on the mix of all
+// channels, so that channels stay in phase with each other.
+func wsola(samples [][]float64, frameRate uint32, factor float64) [][]float64 {
+	inputLength := len(samples[0])
+	outputLength := int(math.Round(float64(inputLength) * factor))
+
+	frameLength := int(wsolaFrameLength.Seconds()*float64(frameRate)) / 2 * 2
+	if frameLength < 4 {
+		frameLength = 4
+	}
+	hop := frameLength / 2
+	tolerance := int(wsolaTolerance.Seconds() * float64(frameRate))
+	stride := int(frameRate) / wsolaSearchRate
+	if stride < 1 {
+		stride = 1
+	}
+
+	// A periodic Hann window sums up to 1 when frames overlap by half.
+	window := make([]float64, frameLength)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(frameLength)))
+	}
+
+	mono := make([]float64, inputLength)
+	for _, channel := range samples {
+		for i, v := range channel {
+			mono[i] += v / float64(len(samples))
+		}
+	}
+
+	output := make([][]float64, len(samples))
+	for c := range output {
+		output[c] = make([]float64, outputLength+frameLength)
+	}
+
+	// The first frame starts before the segment, so that the
+	// beginning is covered by two overlapping windows as well.
+	position := -hop
+	for outputPosition := -hop; outputPosition < outputLength; outputPosition += hop {
+		if outputPosition > -hop {
+			natural := position + hop
+			nominal := int(math.Round(float64(outputPosition) / factor))
+			position = bestAlignment(mono, natural, nominal, tolerance, frameLength, stride)
+		}
+
+		for c, channel := range samples {
+			for i, w := range window {
+				j := outputPosition + i
+				if j < 0 {
+					continue
+				}
+				output[c][j] += w * sampleAt(channel, position+i)
+			}
+		}
+	}
+
+	for c := range output {
+		output[c] = output[c][:outputLength]
+	}
+	return output
+}
+
+// bestAlignment returns the position around `nominal` whose frame looks the most
+// like the frame at `natural`, which continues the previous frame seamlessly.
+func bestAlignment(mono []float64, natural, nominal, tolerance, length, stride int) int {
+	from, to := nominal-tolerance, nominal+tolerance
+	if from < 0 {
+		from = 0
+	}
+
+	// Coarse search, then refine around the best candidate.
+	best := searchAlignment(mono, natural, from, to, length, stride)
+	if stride > 1 {
+		from, to = best-stride, best+stride
+		if from < 0 {
+			from = 0
+		}
+		best = searchAlignment(mono, natural, from, to, length, 1)
+	}
+	return best
+}
+
+func searchAlignment(mono []float64, natural, from, to, length, stride int) int {
+	best, bestScore := from, math.Inf(-1)
+	for candidate := from; candidate <= to; candidate += stride {
+		var score float64
+		for i := 0; i < length; i += stride {
+			score += sampleAt(mono, candidate+i) * sampleAt(mono, natural+i)
+		}
+
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// sampleAt returns the sample at index i, or silence outside of the samples.
+func sampleAt(samples []float64, i int) float64 {
+	if i < 0 || i >= len(samples) {
+		return 0
+	}
+	return samples[i]
+}
@@ -0,0 +1,40 @@
+package godub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// estimateFrequency counts the rising zero crossings of the first channel.
+func estimateFrequency(t *testing.T, seg *AudioSegment) float64 {
+	samples, err := seg.channelSamples()
+	assert.Nil(t, err)
+
+	crossings := 0
+	for i := 1; i < len(samples[0]); i++ {
+		if samples[0][i-1] < 0 && samples[0][i] >= 0 {
+			crossings++
+		}
+	}
+	return float64(crossings) / seg.Duration().Seconds()
+}
+
+func TestTimeStretch(t *testing.T) {
+	seg := newSineSegment(t, 440, 0.5, time.Second, 2, 2)
+
+	stretched, err := seg.TimeStretch(1.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 1500*time.Millisecond, stretched.Duration())
+	assert.InDelta(t, 440, estimateFrequency(t, stretched), 5)
+	assert.InDelta(t, float64(seg.DBFS()), float64(stretched.DBFS()), 0.5)
+
+	faster, err := seg.SpeedUp(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 500*time.Millisecond, faster.Duration())
+	assert.InDelta(t, 440, estimateFrequency(t, faster), 5)
+
+	_, err = seg.TimeStretch(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package godub
+
+import "math"
+
+const (
+	// truePeakOversampling is the oversampling factor of BS.1770 true-peak measurement.
+	truePeakOversampling = 4
+	// truePeakTaps is the length of each phase of the interpolation filter.
+	truePeakTaps = 24
+)
+
+// truePeakFilter holds the phases of the polyphase interpolation filter, phase p computes
+// the sample p/4 of the way between two input samples. Phase 0 is the input sample itself,
+// it isn't filtered.
+var truePeakFilter = newTruePeakFilter(8.6, 0.94)
+
+// newTruePeakFilter builds a Kaiser-windowed sinc with the given window shape and cutoff,
+// relative to the Nyquist frequency of the input. Each phase is normalised to unity gain.
+func newTruePeakFilter(beta, rolloff float64) [][]float64 {
+	half := float64(truePeakTaps / 2)
+	phases := make([][]float64, truePeakOversampling-1)
+	for p := range phases {
+		phases[p] = make([]float64, truePeakTaps)
+
+		var sum float64
+		for j := range phases[p] {
+			// Distance from the interpolated sample to the input sample j, the oldest one first.
+			x := half - 1 - float64(j) + float64(p+1)/truePeakOversampling
+			r := x / half
+			window := besselI0(beta*math.Sqrt(1-r*r)) / besselI0(beta)
+			phases[p][j] = sinc(rolloff*x) * window
+			sum += phases[p][j]
+		}
+
+		for j := range phases[p] {
+			phases[p][j] /= sum
+		}
+	}
+	return phases
+}
+
+// truePeakMeter keeps the true peak of a stream of samples.
+type truePeakMeter struct {
+	// history holds the last truePeakTaps samples twice, so that they can be read as one slice.
+	history []float64
+	pos     int
+	peak    float64
+}
+
+func newTruePeakMeter() *truePeakMeter {
+	return &truePeakMeter{history: make([]float64, 2*truePeakTaps)}
+}
+
+func (m *truePeakMeter) add(sample float64) {
+	m.peak = math.Max(m.peak, math.Abs(sample))
+
+	m.pos = (m.pos + 1) % truePeakTaps
+	m.history[m.pos] = sample
+	m.history[m.pos+truePeakTaps] = sample
+
+	window := m.history[m.pos+1 : m.pos+1+truePeakTaps]
+	for _, phase := range truePeakFilter {
+		var sum float64
+		for j, c := range phase {
+			sum += c * window[j]
+		}
+		m.peak = math.Max(m.peak, math.Abs(sum))
+	}
+}
+
+// flush interpolates the samples left in the filter.
+func (m *truePeakMeter) flush() {
+	for i := 0; i < truePeakTaps/2; i++ {
+		m.add(0)
+	}
+}
+
+// TruePeak returns the true peak of each channel in dBTP, i.e. the highest level of the
+// reconstructed waveform, including inter-sample peaks that sample peaks (MaxDBFS) miss.
+// It oversamples the audio 4 times as described in ITU-R BS.1770, in a single pass that
+// doesn't keep the oversampled audio. A silent channel is SilentVolume.
+func (seg *AudioSegment) TruePeak() ([]Volume, error) {
+	channels := int(seg.channels)
+	frameCount := int(seg.FrameCount())
+	sampleAt := seg.sampleReader()
+
+	meters := make([]*truePeakMeter, channels)
+	for c := range meters {
+		meters[c] = newTruePeakMeter()
+	}
+
+	for i := 0; i < frameCount; i++ {
+		for c, meter := range meters {
+			sample, err := sampleAt(i*channels + c)
+			if err != nil {
+				return nil, err
+			}
+			meter.add(sample)
+		}
+	}
+
+	peaks := make([]Volume, channels)
+	for c, meter := range meters {
+		meter.flush()
+
+		peaks[c] = SilentVolume
+		if meter.peak > 0 {
+			peaks[c] = Volume(20 * math.Log10(meter.peak))
+		}
+	}
+	return peaks, nil
+}
+
+// maxTruePeak returns the highest true peak of all channels.
+func (seg *AudioSegment) maxTruePeak() (Volume, error) {
+	peaks, err := seg.TruePeak()
+	if err != nil {
+		return 0, err
+	}
+
+	max := SilentVolume
+	for _, peak := range peaks {
+		if peak > max {
+			max = peak
+		}
+	}
+	return max, nil
+}
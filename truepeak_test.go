@@ -0,0 +1,68 @@
+package godub
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newInterSampleSine creates a sine at a quarter of the frame rate whose peaks fall
+// between samples, so that the sample peak is 3dB below the true peak.
+func newInterSampleSine(t *testing.T, amplitude float64, format SampleFormat, sampleWidth uint16) *AudioSegment {
+	samples := make([]float64, 48000)
+	for i := range samples {
+		samples[i] = amplitude * math.Sin(math.Pi*float64(i)/2+math.Pi/4)
+	}
+
+	seg, err := NewAudioSegmentFromChannelFloatSamples([][]float64{samples, samples}, 48000)
+	assert.Nil(t, err)
+	seg, err = seg.ForkWithSampleFormat(format, int(sampleWidth))
+	assert.Nil(t, err)
+	return seg
+}
+
+func TestTruePeak(t *testing.T) {
+	formats := []struct {
+		format SampleFormat
+		width  uint16
+	}{
+		{SampleFormatPCM, 1},
+		{SampleFormatPCM, 2},
+		{SampleFormatPCM, 3},
+		{SampleFormatPCM, 4},
+		{SampleFormatFloat, 4},
+	}
+	for _, f := range formats {
+		seg := newInterSampleSine(t, 0.5, f.format, f.width)
+		assert.Equal(t, f.format, seg.SampleFormat())
+		assert.InDelta(t, -9.03, float64(seg.MaxDBFS()), 0.1)
+
+		peaks, err := seg.TruePeak()
+		assert.Nil(t, err)
+		assert.Len(t, peaks, 2)
+		// The abrupt start of the sine rings a little, within the EBU Tech 3341 tolerance.
+		for _, peak := range peaks {
+			assert.InDelta(t, -6.02, float64(peak), 0.2)
+		}
+	}
+
+	silence, err := NewSilentAudioSegment(100, 48000)
+	assert.Nil(t, err)
+	peaks, err := silence.TruePeak()
+	assert.Nil(t, err)
+	assert.Equal(t, []Volume{SilentVolume}, peaks)
+}
+
+func TestNormalizeLoudnessTruePeak(t *testing.T) {
+	seg := newInterSampleSine(t, 0.1, SampleFormatFloat, 4)
+
+	normalized, _, err := seg.NormalizeLoudness(10, -1)
+	assert.Nil(t, err)
+	peaks, err := normalized.TruePeak()
+	assert.Nil(t, err)
+	for _, peak := range peaks {
+		assert.InDelta(t, -1, float64(peak), 0.01)
+	}
+	assert.InDelta(t, -4.01, float64(normalized.MaxDBFS()), 0.2)
+}
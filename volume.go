@@ -9,6 +9,9 @@ import (
 // Volume unit is dBFS.
 type Volume float64
 
+// SilentVolume is the volume of digital silence, its ratio is 0.
+var SilentVolume = Volume(math.Inf(-1))
+
 func NewVolumeFromRatio(ratio float64, denominator float64, useAmplitude bool) Volume {
 	if denominator != 0 {
 		ratio = ratio / denominator
@@ -25,6 +28,11 @@ func NewVolumeFromRatio(ratio float64, denominator float64, useAmplitude bool) V
 	}
 }
 
+// VolumePtr returns a pointer to `volume`, to set optional levels such as GateConfig.Threshold.
+func VolumePtr(volume Volume) *Volume {
+	return &volume
+}
+
 func (volume Volume) String() string {
 	return fmt.Sprintf("%.3fdBFS", float64(volume))
 }
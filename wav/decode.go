@@ -45,7 +45,11 @@ func (d *Decoder) Decode() (*WaveAudio, error) {
 
 	pos := fmtChunk.Position + 8
 	audioFormat := binary.LittleEndian.Uint16(d.buffer[pos : pos+2])
-	if audioFormat != 1 && audioFormat != 0xFFFE {
+	if audioFormat == AudioFormatExtensible && fmtChunk.Size >= 40 {
+		// The first two bytes of the subformat GUID are the format code.
+		audioFormat = binary.LittleEndian.Uint16(d.buffer[pos+24 : pos+26])
+	}
+	if audioFormat != AudioFormatPCM && audioFormat != AudioFormatIEEEFloat && audioFormat != AudioFormatExtensible {
 		return nil, DecodeError(fmt.Sprintf("unknown audio format 0x%X in wav data", audioFormat))
 	}
 
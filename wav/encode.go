@@ -5,15 +5,24 @@ import (
 	"io"
 )
 
-// Encode encodes wave audio to a given writer.
+// Encode encodes wave audio to a given writer. Float audio gets the extended fmt chunk
+// and the fact chunk required for non-PCM formats.
 // WAV file ref: http://www.topherlee.com/software/pcm-tut-wavformat.html
 func Encode(w io.Writer, audio *WaveAudio) error {
+	isFloat := audio.Format == AudioFormatIEEEFloat
+	fmtSize := uint32(16)
+	factSize := uint32(0)
+	if isFloat {
+		fmtSize = 18
+		factSize = 8 + 4
+	}
+
 	// Write RIFF header
 	_, err := w.Write(RiffHeader)
 	if err != nil {
 		return err
 	}
-	riffSize := 4 + 8 + 16 + 8 + audio.DataSize()
+	riffSize := 4 + 8 + fmtSize + factSize + 8 + audio.DataSize()
 	err = binary.Write(w, binary.LittleEndian, uint32(riffSize))
 	if err != nil {
 		return err
@@ -31,7 +40,7 @@ func Encode(w io.Writer, audio *WaveAudio) error {
 		return err
 	}
 	// Write format length
-	err = binary.Write(w, binary.LittleEndian, uint32(16))
+	err = binary.Write(w, binary.LittleEndian, fmtSize)
 	if err != nil {
 		return err
 	}
@@ -66,6 +75,34 @@ func Encode(w io.Writer, audio *WaveAudio) error {
 		return err
 	}
 
+	if isFloat {
+		// Write the size of the format extension, there is none
+		err = binary.Write(w, binary.LittleEndian, uint16(0))
+		if err != nil {
+			return err
+		}
+
+		// Write the number of frames
+		_, err = w.Write(FactHeader)
+		if err != nil {
+			return err
+		}
+
+		err = binary.Write(w, binary.LittleEndian, uint32(4))
+		if err != nil {
+			return err
+		}
+
+		var frameCount uint32
+		if audio.Sound() > 0 {
+			frameCount = audio.DataSize() / uint32(audio.Sound())
+		}
+		err = binary.Write(w, binary.LittleEndian, frameCount)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Write data
 	_, err = w.Write(DataHeader)
 	if err != nil {
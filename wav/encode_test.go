@@ -0,0 +1,55 @@
+package wav
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePCM(t *testing.T) {
+	audio := &WaveAudio{
+		Format:        AudioFormatPCM,
+		Channels:      2,
+		SampleRate:    8000,
+		BitsPerSample: 16,
+		RawData:       []byte{0x01, 0x00, 0x02, 0x00},
+	}
+
+	var buf bytes.Buffer
+	err := Encode(&buf, audio)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		'R', 'I', 'F', 'F', 40, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'f', 'm', 't', ' ', 16, 0, 0, 0,
+		1, 0, 2, 0, 0x40, 0x1f, 0, 0, 0x00, 0x7d, 0, 0, 4, 0, 16, 0,
+		'd', 'a', 't', 'a', 4, 0, 0, 0,
+		0x01, 0x00, 0x02, 0x00,
+	}, buf.Bytes())
+}
+
+func TestEncodeFloat(t *testing.T) {
+	audio := &WaveAudio{
+		Format:        AudioFormatIEEEFloat,
+		Channels:      1,
+		SampleRate:    8000,
+		BitsPerSample: 32,
+		RawData:       []byte{0x00, 0x00, 0x00, 0x3f, 0x00, 0x00, 0x00, 0xbf},
+	}
+
+	var buf bytes.Buffer
+	err := Encode(&buf, audio)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		'R', 'I', 'F', 'F', 58, 0, 0, 0, 'W', 'A', 'V', 'E',
+		'f', 'm', 't', ' ', 18, 0, 0, 0,
+		3, 0, 1, 0, 0x40, 0x1f, 0, 0, 0x00, 0x7d, 0, 0, 4, 0, 32, 0, 0, 0,
+		'f', 'a', 'c', 't', 4, 0, 0, 0, 2, 0, 0, 0,
+		'd', 'a', 't', 'a', 8, 0, 0, 0,
+		0x00, 0x00, 0x00, 0x3f, 0x00, 0x00, 0x00, 0xbf,
+	}, buf.Bytes())
+
+	decoded, err := Decode(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, audio, decoded)
+}
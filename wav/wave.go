@@ -24,7 +24,11 @@
 package wav
 
 const (
-	AudioFormatPCM = 1
+	AudioFormatPCM       = 1
+	AudioFormatIEEEFloat = 3
+	// AudioFormatExtensible stores the actual format in a subformat GUID,
+	// the decoder reports the subformat instead.
+	AudioFormatExtensible = 0xFFFE
 )
 
 var (
@@ -32,6 +36,7 @@ var (
 	RiffHeader = []byte{'R', 'I', 'F', 'F'}
 	FmtHeader  = []byte{'f', 'm', 't', ' '}
 	DataHeader = []byte{'d', 'a', 't', 'a'}
+	FactHeader = []byte{'f', 'a', 'c', 't'}
 )
 
 type Chunk struct {